@@ -0,0 +1,52 @@
+package aws
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/batch"
+)
+
+func newBatchClient(cfg aws.Config) *batch.Client {
+	return batch.NewFromConfig(cfg)
+}
+
+type BatchClient interface {
+	DescribeJobs(ctx context.Context, params *batch.DescribeJobsInput, optFns ...func(*batch.Options)) (*batch.DescribeJobsOutput, error)
+}
+
+type BatchPaginators interface {
+	NewDescribeJobDefinitionsPaginator(params *batch.DescribeJobDefinitionsInput, optFns ...func(*batch.DescribeJobDefinitionsPaginatorOptions)) BatchDescribeJobDefinitionsPaginator
+	NewDescribeJobQueuesPaginator(params *batch.DescribeJobQueuesInput, optFns ...func(*batch.DescribeJobQueuesPaginatorOptions)) BatchDescribeJobQueuesPaginator
+	NewListJobsPaginator(params *batch.ListJobsInput, optFns ...func(*batch.ListJobsPaginatorOptions)) BatchListJobsPaginator
+}
+
+type batchPaginators struct {
+	client *batch.Client
+}
+
+func (b *batchPaginators) NewDescribeJobDefinitionsPaginator(params *batch.DescribeJobDefinitionsInput, optFns ...func(*batch.DescribeJobDefinitionsPaginatorOptions)) BatchDescribeJobDefinitionsPaginator {
+	return batch.NewDescribeJobDefinitionsPaginator(b.client, params, optFns...)
+}
+
+type BatchDescribeJobDefinitionsPaginator interface {
+	HasMorePages() bool
+	NextPage(ctx context.Context, optFns ...func(*batch.Options)) (*batch.DescribeJobDefinitionsOutput, error)
+}
+
+func (b *batchPaginators) NewDescribeJobQueuesPaginator(params *batch.DescribeJobQueuesInput, optFns ...func(*batch.DescribeJobQueuesPaginatorOptions)) BatchDescribeJobQueuesPaginator {
+	return batch.NewDescribeJobQueuesPaginator(b.client, params, optFns...)
+}
+
+type BatchDescribeJobQueuesPaginator interface {
+	HasMorePages() bool
+	NextPage(ctx context.Context, optFns ...func(*batch.Options)) (*batch.DescribeJobQueuesOutput, error)
+}
+
+func (b *batchPaginators) NewListJobsPaginator(params *batch.ListJobsInput, optFns ...func(*batch.ListJobsPaginatorOptions)) BatchListJobsPaginator {
+	return batch.NewListJobsPaginator(b.client, params, optFns...)
+}
+
+type BatchListJobsPaginator interface {
+	HasMorePages() bool
+	NextPage(ctx context.Context, optFns ...func(*batch.Options)) (*batch.ListJobsOutput, error)
+}
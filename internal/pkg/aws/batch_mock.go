@@ -0,0 +1,307 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/pkg/aws/batch.go
+
+// Package aws is a generated GoMock package.
+package aws
+
+import (
+	context "context"
+	reflect "reflect"
+
+	batch "github.com/aws/aws-sdk-go-v2/service/batch"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBatchClient is a mock of BatchClient interface.
+type MockBatchClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchClientMockRecorder
+}
+
+// MockBatchClientMockRecorder is the mock recorder for MockBatchClient.
+type MockBatchClientMockRecorder struct {
+	mock *MockBatchClient
+}
+
+// NewMockBatchClient creates a new mock instance.
+func NewMockBatchClient(ctrl *gomock.Controller) *MockBatchClient {
+	mock := &MockBatchClient{ctrl: ctrl}
+	mock.recorder = &MockBatchClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchClient) EXPECT() *MockBatchClientMockRecorder {
+	return m.recorder
+}
+
+// DescribeJobs mocks base method.
+func (m *MockBatchClient) DescribeJobs(ctx context.Context, params *batch.DescribeJobsInput, optFns ...func(*batch.Options)) (*batch.DescribeJobsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeJobs", varargs...)
+	ret0, _ := ret[0].(*batch.DescribeJobsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeJobs indicates an expected call of DescribeJobs.
+func (mr *MockBatchClientMockRecorder) DescribeJobs(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeJobs", reflect.TypeOf((*MockBatchClient)(nil).DescribeJobs), varargs...)
+}
+
+// MockBatchPaginators is a mock of BatchPaginators interface.
+type MockBatchPaginators struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchPaginatorsMockRecorder
+}
+
+// MockBatchPaginatorsMockRecorder is the mock recorder for MockBatchPaginators.
+type MockBatchPaginatorsMockRecorder struct {
+	mock *MockBatchPaginators
+}
+
+// NewMockBatchPaginators creates a new mock instance.
+func NewMockBatchPaginators(ctrl *gomock.Controller) *MockBatchPaginators {
+	mock := &MockBatchPaginators{ctrl: ctrl}
+	mock.recorder = &MockBatchPaginatorsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchPaginators) EXPECT() *MockBatchPaginatorsMockRecorder {
+	return m.recorder
+}
+
+// NewDescribeJobDefinitionsPaginator mocks base method.
+func (m *MockBatchPaginators) NewDescribeJobDefinitionsPaginator(params *batch.DescribeJobDefinitionsInput, optFns ...func(*batch.DescribeJobDefinitionsPaginatorOptions)) BatchDescribeJobDefinitionsPaginator {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewDescribeJobDefinitionsPaginator", varargs...)
+	ret0, _ := ret[0].(BatchDescribeJobDefinitionsPaginator)
+	return ret0
+}
+
+// NewDescribeJobDefinitionsPaginator indicates an expected call of NewDescribeJobDefinitionsPaginator.
+func (mr *MockBatchPaginatorsMockRecorder) NewDescribeJobDefinitionsPaginator(params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewDescribeJobDefinitionsPaginator", reflect.TypeOf((*MockBatchPaginators)(nil).NewDescribeJobDefinitionsPaginator), varargs...)
+}
+
+// NewDescribeJobQueuesPaginator mocks base method.
+func (m *MockBatchPaginators) NewDescribeJobQueuesPaginator(params *batch.DescribeJobQueuesInput, optFns ...func(*batch.DescribeJobQueuesPaginatorOptions)) BatchDescribeJobQueuesPaginator {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewDescribeJobQueuesPaginator", varargs...)
+	ret0, _ := ret[0].(BatchDescribeJobQueuesPaginator)
+	return ret0
+}
+
+// NewDescribeJobQueuesPaginator indicates an expected call of NewDescribeJobQueuesPaginator.
+func (mr *MockBatchPaginatorsMockRecorder) NewDescribeJobQueuesPaginator(params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewDescribeJobQueuesPaginator", reflect.TypeOf((*MockBatchPaginators)(nil).NewDescribeJobQueuesPaginator), varargs...)
+}
+
+// NewListJobsPaginator mocks base method.
+func (m *MockBatchPaginators) NewListJobsPaginator(params *batch.ListJobsInput, optFns ...func(*batch.ListJobsPaginatorOptions)) BatchListJobsPaginator {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListJobsPaginator", varargs...)
+	ret0, _ := ret[0].(BatchListJobsPaginator)
+	return ret0
+}
+
+// NewListJobsPaginator indicates an expected call of NewListJobsPaginator.
+func (mr *MockBatchPaginatorsMockRecorder) NewListJobsPaginator(params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListJobsPaginator", reflect.TypeOf((*MockBatchPaginators)(nil).NewListJobsPaginator), varargs...)
+}
+
+// MockBatchDescribeJobDefinitionsPaginator is a mock of BatchDescribeJobDefinitionsPaginator interface.
+type MockBatchDescribeJobDefinitionsPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchDescribeJobDefinitionsPaginatorMockRecorder
+}
+
+// MockBatchDescribeJobDefinitionsPaginatorMockRecorder is the mock recorder for MockBatchDescribeJobDefinitionsPaginator.
+type MockBatchDescribeJobDefinitionsPaginatorMockRecorder struct {
+	mock *MockBatchDescribeJobDefinitionsPaginator
+}
+
+// NewMockBatchDescribeJobDefinitionsPaginator creates a new mock instance.
+func NewMockBatchDescribeJobDefinitionsPaginator(ctrl *gomock.Controller) *MockBatchDescribeJobDefinitionsPaginator {
+	mock := &MockBatchDescribeJobDefinitionsPaginator{ctrl: ctrl}
+	mock.recorder = &MockBatchDescribeJobDefinitionsPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchDescribeJobDefinitionsPaginator) EXPECT() *MockBatchDescribeJobDefinitionsPaginatorMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockBatchDescribeJobDefinitionsPaginator) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockBatchDescribeJobDefinitionsPaginatorMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockBatchDescribeJobDefinitionsPaginator)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockBatchDescribeJobDefinitionsPaginator) NextPage(ctx context.Context, optFns ...func(*batch.Options)) (*batch.DescribeJobDefinitionsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*batch.DescribeJobDefinitionsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockBatchDescribeJobDefinitionsPaginatorMockRecorder) NextPage(ctx interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockBatchDescribeJobDefinitionsPaginator)(nil).NextPage), varargs...)
+}
+
+// MockBatchDescribeJobQueuesPaginator is a mock of BatchDescribeJobQueuesPaginator interface.
+type MockBatchDescribeJobQueuesPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchDescribeJobQueuesPaginatorMockRecorder
+}
+
+// MockBatchDescribeJobQueuesPaginatorMockRecorder is the mock recorder for MockBatchDescribeJobQueuesPaginator.
+type MockBatchDescribeJobQueuesPaginatorMockRecorder struct {
+	mock *MockBatchDescribeJobQueuesPaginator
+}
+
+// NewMockBatchDescribeJobQueuesPaginator creates a new mock instance.
+func NewMockBatchDescribeJobQueuesPaginator(ctrl *gomock.Controller) *MockBatchDescribeJobQueuesPaginator {
+	mock := &MockBatchDescribeJobQueuesPaginator{ctrl: ctrl}
+	mock.recorder = &MockBatchDescribeJobQueuesPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchDescribeJobQueuesPaginator) EXPECT() *MockBatchDescribeJobQueuesPaginatorMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockBatchDescribeJobQueuesPaginator) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockBatchDescribeJobQueuesPaginatorMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockBatchDescribeJobQueuesPaginator)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockBatchDescribeJobQueuesPaginator) NextPage(ctx context.Context, optFns ...func(*batch.Options)) (*batch.DescribeJobQueuesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*batch.DescribeJobQueuesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockBatchDescribeJobQueuesPaginatorMockRecorder) NextPage(ctx interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockBatchDescribeJobQueuesPaginator)(nil).NextPage), varargs...)
+}
+
+// MockBatchListJobsPaginator is a mock of BatchListJobsPaginator interface.
+type MockBatchListJobsPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchListJobsPaginatorMockRecorder
+}
+
+// MockBatchListJobsPaginatorMockRecorder is the mock recorder for MockBatchListJobsPaginator.
+type MockBatchListJobsPaginatorMockRecorder struct {
+	mock *MockBatchListJobsPaginator
+}
+
+// NewMockBatchListJobsPaginator creates a new mock instance.
+func NewMockBatchListJobsPaginator(ctrl *gomock.Controller) *MockBatchListJobsPaginator {
+	mock := &MockBatchListJobsPaginator{ctrl: ctrl}
+	mock.recorder = &MockBatchListJobsPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchListJobsPaginator) EXPECT() *MockBatchListJobsPaginatorMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockBatchListJobsPaginator) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockBatchListJobsPaginatorMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockBatchListJobsPaginator)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockBatchListJobsPaginator) NextPage(ctx context.Context, optFns ...func(*batch.Options)) (*batch.ListJobsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*batch.ListJobsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockBatchListJobsPaginatorMockRecorder) NextPage(ctx interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockBatchListJobsPaginator)(nil).NextPage), varargs...)
+}
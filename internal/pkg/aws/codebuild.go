@@ -0,0 +1,54 @@
+package aws
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codebuild"
+)
+
+func newCodeBuildClient(cfg aws.Config) *codebuild.Client {
+	return codebuild.NewFromConfig(cfg)
+}
+
+type CodeBuildClient interface {
+	BatchGetProjects(ctx context.Context, params *codebuild.BatchGetProjectsInput, optFns ...func(*codebuild.Options)) (*codebuild.BatchGetProjectsOutput, error)
+	BatchGetBuildBatches(ctx context.Context, params *codebuild.BatchGetBuildBatchesInput, optFns ...func(*codebuild.Options)) (*codebuild.BatchGetBuildBatchesOutput, error)
+	BatchGetBuilds(ctx context.Context, params *codebuild.BatchGetBuildsInput, optFns ...func(*codebuild.Options)) (*codebuild.BatchGetBuildsOutput, error)
+}
+
+type CodeBuildPaginators interface {
+	NewListProjectsPaginator(params *codebuild.ListProjectsInput, optFns ...func(*codebuild.ListProjectsPaginatorOptions)) CodeBuildListProjectsPaginator
+	NewListBuildBatchesPaginator(params *codebuild.ListBuildBatchesInput, optFns ...func(*codebuild.ListBuildBatchesPaginatorOptions)) CodeBuildListBuildBatchesPaginator
+	NewListBuildsForProjectPaginator(params *codebuild.ListBuildsForProjectInput, optFns ...func(*codebuild.ListBuildsForProjectPaginatorOptions)) CodeBuildListBuildsForProjectPaginator
+}
+
+type codeBuildPaginators struct {
+	client *codebuild.Client
+}
+
+func (c *codeBuildPaginators) NewListProjectsPaginator(params *codebuild.ListProjectsInput, optFns ...func(*codebuild.ListProjectsPaginatorOptions)) CodeBuildListProjectsPaginator {
+	return codebuild.NewListProjectsPaginator(c.client, params, optFns...)
+}
+
+type CodeBuildListProjectsPaginator interface {
+	HasMorePages() bool
+	NextPage(ctx context.Context, optFns ...func(*codebuild.Options)) (*codebuild.ListProjectsOutput, error)
+}
+
+func (c *codeBuildPaginators) NewListBuildBatchesPaginator(params *codebuild.ListBuildBatchesInput, optFns ...func(*codebuild.ListBuildBatchesPaginatorOptions)) CodeBuildListBuildBatchesPaginator {
+	return codebuild.NewListBuildBatchesPaginator(c.client, params, optFns...)
+}
+
+type CodeBuildListBuildBatchesPaginator interface {
+	HasMorePages() bool
+	NextPage(ctx context.Context, optFns ...func(*codebuild.Options)) (*codebuild.ListBuildBatchesOutput, error)
+}
+
+func (c *codeBuildPaginators) NewListBuildsForProjectPaginator(params *codebuild.ListBuildsForProjectInput, optFns ...func(*codebuild.ListBuildsForProjectPaginatorOptions)) CodeBuildListBuildsForProjectPaginator {
+	return codebuild.NewListBuildsForProjectPaginator(c.client, params, optFns...)
+}
+
+type CodeBuildListBuildsForProjectPaginator interface {
+	HasMorePages() bool
+	NextPage(ctx context.Context, optFns ...func(*codebuild.Options)) (*codebuild.ListBuildsForProjectOutput, error)
+}
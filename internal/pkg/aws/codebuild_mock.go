@@ -0,0 +1,347 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/pkg/aws/codebuild.go
+
+// Package aws is a generated GoMock package.
+package aws
+
+import (
+	context "context"
+	reflect "reflect"
+
+	codebuild "github.com/aws/aws-sdk-go-v2/service/codebuild"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCodeBuildClient is a mock of CodeBuildClient interface.
+type MockCodeBuildClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodeBuildClientMockRecorder
+}
+
+// MockCodeBuildClientMockRecorder is the mock recorder for MockCodeBuildClient.
+type MockCodeBuildClientMockRecorder struct {
+	mock *MockCodeBuildClient
+}
+
+// NewMockCodeBuildClient creates a new mock instance.
+func NewMockCodeBuildClient(ctrl *gomock.Controller) *MockCodeBuildClient {
+	mock := &MockCodeBuildClient{ctrl: ctrl}
+	mock.recorder = &MockCodeBuildClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodeBuildClient) EXPECT() *MockCodeBuildClientMockRecorder {
+	return m.recorder
+}
+
+// BatchGetBuildBatches mocks base method.
+func (m *MockCodeBuildClient) BatchGetBuildBatches(ctx context.Context, params *codebuild.BatchGetBuildBatchesInput, optFns ...func(*codebuild.Options)) (*codebuild.BatchGetBuildBatchesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BatchGetBuildBatches", varargs...)
+	ret0, _ := ret[0].(*codebuild.BatchGetBuildBatchesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchGetBuildBatches indicates an expected call of BatchGetBuildBatches.
+func (mr *MockCodeBuildClientMockRecorder) BatchGetBuildBatches(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGetBuildBatches", reflect.TypeOf((*MockCodeBuildClient)(nil).BatchGetBuildBatches), varargs...)
+}
+
+// BatchGetBuilds mocks base method.
+func (m *MockCodeBuildClient) BatchGetBuilds(ctx context.Context, params *codebuild.BatchGetBuildsInput, optFns ...func(*codebuild.Options)) (*codebuild.BatchGetBuildsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BatchGetBuilds", varargs...)
+	ret0, _ := ret[0].(*codebuild.BatchGetBuildsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchGetBuilds indicates an expected call of BatchGetBuilds.
+func (mr *MockCodeBuildClientMockRecorder) BatchGetBuilds(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGetBuilds", reflect.TypeOf((*MockCodeBuildClient)(nil).BatchGetBuilds), varargs...)
+}
+
+// BatchGetProjects mocks base method.
+func (m *MockCodeBuildClient) BatchGetProjects(ctx context.Context, params *codebuild.BatchGetProjectsInput, optFns ...func(*codebuild.Options)) (*codebuild.BatchGetProjectsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BatchGetProjects", varargs...)
+	ret0, _ := ret[0].(*codebuild.BatchGetProjectsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchGetProjects indicates an expected call of BatchGetProjects.
+func (mr *MockCodeBuildClientMockRecorder) BatchGetProjects(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGetProjects", reflect.TypeOf((*MockCodeBuildClient)(nil).BatchGetProjects), varargs...)
+}
+
+// MockCodeBuildPaginators is a mock of CodeBuildPaginators interface.
+type MockCodeBuildPaginators struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodeBuildPaginatorsMockRecorder
+}
+
+// MockCodeBuildPaginatorsMockRecorder is the mock recorder for MockCodeBuildPaginators.
+type MockCodeBuildPaginatorsMockRecorder struct {
+	mock *MockCodeBuildPaginators
+}
+
+// NewMockCodeBuildPaginators creates a new mock instance.
+func NewMockCodeBuildPaginators(ctrl *gomock.Controller) *MockCodeBuildPaginators {
+	mock := &MockCodeBuildPaginators{ctrl: ctrl}
+	mock.recorder = &MockCodeBuildPaginatorsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodeBuildPaginators) EXPECT() *MockCodeBuildPaginatorsMockRecorder {
+	return m.recorder
+}
+
+// NewListBuildBatchesPaginator mocks base method.
+func (m *MockCodeBuildPaginators) NewListBuildBatchesPaginator(params *codebuild.ListBuildBatchesInput, optFns ...func(*codebuild.ListBuildBatchesPaginatorOptions)) CodeBuildListBuildBatchesPaginator {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListBuildBatchesPaginator", varargs...)
+	ret0, _ := ret[0].(CodeBuildListBuildBatchesPaginator)
+	return ret0
+}
+
+// NewListBuildBatchesPaginator indicates an expected call of NewListBuildBatchesPaginator.
+func (mr *MockCodeBuildPaginatorsMockRecorder) NewListBuildBatchesPaginator(params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListBuildBatchesPaginator", reflect.TypeOf((*MockCodeBuildPaginators)(nil).NewListBuildBatchesPaginator), varargs...)
+}
+
+// NewListBuildsForProjectPaginator mocks base method.
+func (m *MockCodeBuildPaginators) NewListBuildsForProjectPaginator(params *codebuild.ListBuildsForProjectInput, optFns ...func(*codebuild.ListBuildsForProjectPaginatorOptions)) CodeBuildListBuildsForProjectPaginator {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListBuildsForProjectPaginator", varargs...)
+	ret0, _ := ret[0].(CodeBuildListBuildsForProjectPaginator)
+	return ret0
+}
+
+// NewListBuildsForProjectPaginator indicates an expected call of NewListBuildsForProjectPaginator.
+func (mr *MockCodeBuildPaginatorsMockRecorder) NewListBuildsForProjectPaginator(params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListBuildsForProjectPaginator", reflect.TypeOf((*MockCodeBuildPaginators)(nil).NewListBuildsForProjectPaginator), varargs...)
+}
+
+// NewListProjectsPaginator mocks base method.
+func (m *MockCodeBuildPaginators) NewListProjectsPaginator(params *codebuild.ListProjectsInput, optFns ...func(*codebuild.ListProjectsPaginatorOptions)) CodeBuildListProjectsPaginator {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListProjectsPaginator", varargs...)
+	ret0, _ := ret[0].(CodeBuildListProjectsPaginator)
+	return ret0
+}
+
+// NewListProjectsPaginator indicates an expected call of NewListProjectsPaginator.
+func (mr *MockCodeBuildPaginatorsMockRecorder) NewListProjectsPaginator(params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListProjectsPaginator", reflect.TypeOf((*MockCodeBuildPaginators)(nil).NewListProjectsPaginator), varargs...)
+}
+
+// MockCodeBuildListProjectsPaginator is a mock of CodeBuildListProjectsPaginator interface.
+type MockCodeBuildListProjectsPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodeBuildListProjectsPaginatorMockRecorder
+}
+
+// MockCodeBuildListProjectsPaginatorMockRecorder is the mock recorder for MockCodeBuildListProjectsPaginator.
+type MockCodeBuildListProjectsPaginatorMockRecorder struct {
+	mock *MockCodeBuildListProjectsPaginator
+}
+
+// NewMockCodeBuildListProjectsPaginator creates a new mock instance.
+func NewMockCodeBuildListProjectsPaginator(ctrl *gomock.Controller) *MockCodeBuildListProjectsPaginator {
+	mock := &MockCodeBuildListProjectsPaginator{ctrl: ctrl}
+	mock.recorder = &MockCodeBuildListProjectsPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodeBuildListProjectsPaginator) EXPECT() *MockCodeBuildListProjectsPaginatorMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockCodeBuildListProjectsPaginator) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockCodeBuildListProjectsPaginatorMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockCodeBuildListProjectsPaginator)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockCodeBuildListProjectsPaginator) NextPage(ctx context.Context, optFns ...func(*codebuild.Options)) (*codebuild.ListProjectsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*codebuild.ListProjectsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockCodeBuildListProjectsPaginatorMockRecorder) NextPage(ctx interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockCodeBuildListProjectsPaginator)(nil).NextPage), varargs...)
+}
+
+// MockCodeBuildListBuildBatchesPaginator is a mock of CodeBuildListBuildBatchesPaginator interface.
+type MockCodeBuildListBuildBatchesPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodeBuildListBuildBatchesPaginatorMockRecorder
+}
+
+// MockCodeBuildListBuildBatchesPaginatorMockRecorder is the mock recorder for MockCodeBuildListBuildBatchesPaginator.
+type MockCodeBuildListBuildBatchesPaginatorMockRecorder struct {
+	mock *MockCodeBuildListBuildBatchesPaginator
+}
+
+// NewMockCodeBuildListBuildBatchesPaginator creates a new mock instance.
+func NewMockCodeBuildListBuildBatchesPaginator(ctrl *gomock.Controller) *MockCodeBuildListBuildBatchesPaginator {
+	mock := &MockCodeBuildListBuildBatchesPaginator{ctrl: ctrl}
+	mock.recorder = &MockCodeBuildListBuildBatchesPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodeBuildListBuildBatchesPaginator) EXPECT() *MockCodeBuildListBuildBatchesPaginatorMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockCodeBuildListBuildBatchesPaginator) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockCodeBuildListBuildBatchesPaginatorMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockCodeBuildListBuildBatchesPaginator)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockCodeBuildListBuildBatchesPaginator) NextPage(ctx context.Context, optFns ...func(*codebuild.Options)) (*codebuild.ListBuildBatchesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*codebuild.ListBuildBatchesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockCodeBuildListBuildBatchesPaginatorMockRecorder) NextPage(ctx interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockCodeBuildListBuildBatchesPaginator)(nil).NextPage), varargs...)
+}
+
+// MockCodeBuildListBuildsForProjectPaginator is a mock of CodeBuildListBuildsForProjectPaginator interface.
+type MockCodeBuildListBuildsForProjectPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodeBuildListBuildsForProjectPaginatorMockRecorder
+}
+
+// MockCodeBuildListBuildsForProjectPaginatorMockRecorder is the mock recorder for MockCodeBuildListBuildsForProjectPaginator.
+type MockCodeBuildListBuildsForProjectPaginatorMockRecorder struct {
+	mock *MockCodeBuildListBuildsForProjectPaginator
+}
+
+// NewMockCodeBuildListBuildsForProjectPaginator creates a new mock instance.
+func NewMockCodeBuildListBuildsForProjectPaginator(ctrl *gomock.Controller) *MockCodeBuildListBuildsForProjectPaginator {
+	mock := &MockCodeBuildListBuildsForProjectPaginator{ctrl: ctrl}
+	mock.recorder = &MockCodeBuildListBuildsForProjectPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodeBuildListBuildsForProjectPaginator) EXPECT() *MockCodeBuildListBuildsForProjectPaginatorMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockCodeBuildListBuildsForProjectPaginator) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockCodeBuildListBuildsForProjectPaginatorMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockCodeBuildListBuildsForProjectPaginator)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockCodeBuildListBuildsForProjectPaginator) NextPage(ctx context.Context, optFns ...func(*codebuild.Options)) (*codebuild.ListBuildsForProjectOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*codebuild.ListBuildsForProjectOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockCodeBuildListBuildsForProjectPaginatorMockRecorder) NextPage(ctx interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockCodeBuildListBuildsForProjectPaginator)(nil).NextPage), varargs...)
+}
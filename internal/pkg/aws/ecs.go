@@ -13,11 +13,13 @@ func newEcsClient(cfg aws.Config) *ecs.Client {
 type EcsClient interface {
 	DescribeServices(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error)
 	DescribeTaskDefinition(ctx context.Context, params *ecs.DescribeTaskDefinitionInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error)
+	DescribeTasks(ctx context.Context, params *ecs.DescribeTasksInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error)
 }
 
 type EcsPaginators interface {
 	NewListClustersPaginator(params *ecs.ListClustersInput, optFns ...func(*ecs.ListClustersPaginatorOptions)) EcsListClustersPaginator
 	NewListServicesPaginator(params *ecs.ListServicesInput, optFns ...func(*ecs.ListServicesPaginatorOptions)) EcsListServicesPaginator
+	NewListTasksPaginator(params *ecs.ListTasksInput, optFns ...func(*ecs.ListTasksPaginatorOptions)) EcsListTasksPaginator
 }
 
 type ecsPaginators struct {
@@ -41,3 +43,12 @@ type EcsListServicesPaginator interface {
 	HasMorePages() bool
 	NextPage(ctx context.Context, optFns ...func(*ecs.Options)) (*ecs.ListServicesOutput, error)
 }
+
+func (e *ecsPaginators) NewListTasksPaginator(params *ecs.ListTasksInput, optFns ...func(*ecs.ListTasksPaginatorOptions)) EcsListTasksPaginator {
+	return ecs.NewListTasksPaginator(e.client, params, optFns...)
+}
+
+type EcsListTasksPaginator interface {
+	HasMorePages() bool
+	NextPage(ctx context.Context, optFns ...func(*ecs.Options)) (*ecs.ListTasksOutput, error)
+}
@@ -75,6 +75,26 @@ func (mr *MockEcsClientMockRecorder) DescribeTaskDefinition(ctx, params interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeTaskDefinition", reflect.TypeOf((*MockEcsClient)(nil).DescribeTaskDefinition), varargs...)
 }
 
+// DescribeTasks mocks base method.
+func (m *MockEcsClient) DescribeTasks(ctx context.Context, params *ecs.DescribeTasksInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeTasks", varargs...)
+	ret0, _ := ret[0].(*ecs.DescribeTasksOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeTasks indicates an expected call of DescribeTasks.
+func (mr *MockEcsClientMockRecorder) DescribeTasks(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeTasks", reflect.TypeOf((*MockEcsClient)(nil).DescribeTasks), varargs...)
+}
+
 // MockEcsPaginators is a mock of EcsPaginators interface.
 type MockEcsPaginators struct {
 	ctrl     *gomock.Controller
@@ -136,6 +156,25 @@ func (mr *MockEcsPaginatorsMockRecorder) NewListServicesPaginator(params interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListServicesPaginator", reflect.TypeOf((*MockEcsPaginators)(nil).NewListServicesPaginator), varargs...)
 }
 
+// NewListTasksPaginator mocks base method.
+func (m *MockEcsPaginators) NewListTasksPaginator(params *ecs.ListTasksInput, optFns ...func(*ecs.ListTasksPaginatorOptions)) EcsListTasksPaginator {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListTasksPaginator", varargs...)
+	ret0, _ := ret[0].(EcsListTasksPaginator)
+	return ret0
+}
+
+// NewListTasksPaginator indicates an expected call of NewListTasksPaginator.
+func (mr *MockEcsPaginatorsMockRecorder) NewListTasksPaginator(params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListTasksPaginator", reflect.TypeOf((*MockEcsPaginators)(nil).NewListTasksPaginator), varargs...)
+}
+
 // MockEcsListClustersPaginator is a mock of EcsListClustersPaginator interface.
 type MockEcsListClustersPaginator struct {
 	ctrl     *gomock.Controller
@@ -249,3 +288,60 @@ func (mr *MockEcsListServicesPaginatorMockRecorder) NextPage(ctx interface{}, op
 	varargs := append([]interface{}{ctx}, optFns...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockEcsListServicesPaginator)(nil).NextPage), varargs...)
 }
+
+// MockEcsListTasksPaginator is a mock of EcsListTasksPaginator interface.
+type MockEcsListTasksPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockEcsListTasksPaginatorMockRecorder
+}
+
+// MockEcsListTasksPaginatorMockRecorder is the mock recorder for MockEcsListTasksPaginator.
+type MockEcsListTasksPaginatorMockRecorder struct {
+	mock *MockEcsListTasksPaginator
+}
+
+// NewMockEcsListTasksPaginator creates a new mock instance.
+func NewMockEcsListTasksPaginator(ctrl *gomock.Controller) *MockEcsListTasksPaginator {
+	mock := &MockEcsListTasksPaginator{ctrl: ctrl}
+	mock.recorder = &MockEcsListTasksPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEcsListTasksPaginator) EXPECT() *MockEcsListTasksPaginatorMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockEcsListTasksPaginator) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockEcsListTasksPaginatorMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockEcsListTasksPaginator)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockEcsListTasksPaginator) NextPage(ctx context.Context, optFns ...func(*ecs.Options)) (*ecs.ListTasksOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*ecs.ListTasksOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockEcsListTasksPaginatorMockRecorder) NextPage(ctx interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockEcsListTasksPaginator)(nil).NextPage), varargs...)
+}
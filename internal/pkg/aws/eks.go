@@ -0,0 +1,15 @@
+package aws
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+)
+
+func newEksClient(cfg aws.Config) *eks.Client {
+	return eks.NewFromConfig(cfg)
+}
+
+type EksClient interface {
+	DescribeCluster(ctx context.Context, params *eks.DescribeClusterInput, optFns ...func(*eks.Options)) (*eks.DescribeClusterOutput, error)
+}
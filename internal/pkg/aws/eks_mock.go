@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/pkg/aws/eks.go
+
+// Package aws is a generated GoMock package.
+package aws
+
+import (
+	context "context"
+	reflect "reflect"
+
+	eks "github.com/aws/aws-sdk-go-v2/service/eks"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockEksClient is a mock of EksClient interface.
+type MockEksClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockEksClientMockRecorder
+}
+
+// MockEksClientMockRecorder is the mock recorder for MockEksClient.
+type MockEksClientMockRecorder struct {
+	mock *MockEksClient
+}
+
+// NewMockEksClient creates a new mock instance.
+func NewMockEksClient(ctrl *gomock.Controller) *MockEksClient {
+	mock := &MockEksClient{ctrl: ctrl}
+	mock.recorder = &MockEksClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEksClient) EXPECT() *MockEksClientMockRecorder {
+	return m.recorder
+}
+
+// DescribeCluster mocks base method.
+func (m *MockEksClient) DescribeCluster(ctx context.Context, params *eks.DescribeClusterInput, optFns ...func(*eks.Options)) (*eks.DescribeClusterOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeCluster", varargs...)
+	ret0, _ := ret[0].(*eks.DescribeClusterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeCluster indicates an expected call of DescribeCluster.
+func (mr *MockEksClientMockRecorder) DescribeCluster(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCluster", reflect.TypeOf((*MockEksClient)(nil).DescribeCluster), varargs...)
+}
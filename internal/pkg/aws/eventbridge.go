@@ -0,0 +1,107 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+)
+
+func newEventBridgeClient(cfg aws.Config) *eventbridge.Client {
+	return eventbridge.NewFromConfig(cfg)
+}
+
+// EventBridgePaginators provides paginators for EventBridge operations. The SDK does not generate them for
+// EventBridge, so they are implemented here with the same interface as the generated ones.
+type EventBridgePaginators interface {
+	NewListRulesPaginator(params *eventbridge.ListRulesInput) EventBridgeListRulesPaginator
+	NewListTargetsByRulePaginator(params *eventbridge.ListTargetsByRuleInput) EventBridgeListTargetsByRulePaginator
+}
+
+type eventBridgePaginators struct {
+	client *eventbridge.Client
+}
+
+func (e *eventBridgePaginators) NewListRulesPaginator(params *eventbridge.ListRulesInput) EventBridgeListRulesPaginator {
+	return &eventBridgeListRulesPaginator{
+		client:    e.client,
+		params:    params,
+		firstPage: true,
+	}
+}
+
+type EventBridgeListRulesPaginator interface {
+	HasMorePages() bool
+	NextPage(ctx context.Context, optFns ...func(*eventbridge.Options)) (*eventbridge.ListRulesOutput, error)
+}
+
+type eventBridgeListRulesPaginator struct {
+	client    *eventbridge.Client
+	params    *eventbridge.ListRulesInput
+	nextToken *string
+	firstPage bool
+}
+
+func (p *eventBridgeListRulesPaginator) HasMorePages() bool {
+	return p.firstPage || (p.nextToken != nil && len(*p.nextToken) != 0)
+}
+
+func (p *eventBridgeListRulesPaginator) NextPage(ctx context.Context, optFns ...func(*eventbridge.Options)) (*eventbridge.ListRulesOutput, error) {
+	if !p.HasMorePages() {
+		return nil, errors.New("no more pages available")
+	}
+
+	params := *p.params
+	params.NextToken = p.nextToken
+
+	result, err := p.client.ListRules(ctx, &params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	p.firstPage = false
+	p.nextToken = result.NextToken
+
+	return result, nil
+}
+
+func (e *eventBridgePaginators) NewListTargetsByRulePaginator(params *eventbridge.ListTargetsByRuleInput) EventBridgeListTargetsByRulePaginator {
+	return &eventBridgeListTargetsByRulePaginator{
+		client:    e.client,
+		params:    params,
+		firstPage: true,
+	}
+}
+
+type EventBridgeListTargetsByRulePaginator interface {
+	HasMorePages() bool
+	NextPage(ctx context.Context, optFns ...func(*eventbridge.Options)) (*eventbridge.ListTargetsByRuleOutput, error)
+}
+
+type eventBridgeListTargetsByRulePaginator struct {
+	client    *eventbridge.Client
+	params    *eventbridge.ListTargetsByRuleInput
+	nextToken *string
+	firstPage bool
+}
+
+func (p *eventBridgeListTargetsByRulePaginator) HasMorePages() bool {
+	return p.firstPage || (p.nextToken != nil && len(*p.nextToken) != 0)
+}
+
+func (p *eventBridgeListTargetsByRulePaginator) NextPage(ctx context.Context, optFns ...func(*eventbridge.Options)) (*eventbridge.ListTargetsByRuleOutput, error) {
+	if !p.HasMorePages() {
+		return nil, errors.New("no more pages available")
+	}
+
+	params := *p.params
+	params.NextToken = p.nextToken
+
+	result, err := p.client.ListTargetsByRule(ctx, &params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	p.firstPage = false
+	p.nextToken = result.NextToken
+
+	return result, nil
+}
@@ -0,0 +1,178 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/pkg/aws/eventbridge.go
+
+// Package aws is a generated GoMock package.
+package aws
+
+import (
+	context "context"
+	reflect "reflect"
+
+	eventbridge "github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockEventBridgePaginators is a mock of EventBridgePaginators interface.
+type MockEventBridgePaginators struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventBridgePaginatorsMockRecorder
+}
+
+// MockEventBridgePaginatorsMockRecorder is the mock recorder for MockEventBridgePaginators.
+type MockEventBridgePaginatorsMockRecorder struct {
+	mock *MockEventBridgePaginators
+}
+
+// NewMockEventBridgePaginators creates a new mock instance.
+func NewMockEventBridgePaginators(ctrl *gomock.Controller) *MockEventBridgePaginators {
+	mock := &MockEventBridgePaginators{ctrl: ctrl}
+	mock.recorder = &MockEventBridgePaginatorsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventBridgePaginators) EXPECT() *MockEventBridgePaginatorsMockRecorder {
+	return m.recorder
+}
+
+// NewListRulesPaginator mocks base method.
+func (m *MockEventBridgePaginators) NewListRulesPaginator(params *eventbridge.ListRulesInput) EventBridgeListRulesPaginator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewListRulesPaginator", params)
+	ret0, _ := ret[0].(EventBridgeListRulesPaginator)
+	return ret0
+}
+
+// NewListRulesPaginator indicates an expected call of NewListRulesPaginator.
+func (mr *MockEventBridgePaginatorsMockRecorder) NewListRulesPaginator(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListRulesPaginator", reflect.TypeOf((*MockEventBridgePaginators)(nil).NewListRulesPaginator), params)
+}
+
+// NewListTargetsByRulePaginator mocks base method.
+func (m *MockEventBridgePaginators) NewListTargetsByRulePaginator(params *eventbridge.ListTargetsByRuleInput) EventBridgeListTargetsByRulePaginator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewListTargetsByRulePaginator", params)
+	ret0, _ := ret[0].(EventBridgeListTargetsByRulePaginator)
+	return ret0
+}
+
+// NewListTargetsByRulePaginator indicates an expected call of NewListTargetsByRulePaginator.
+func (mr *MockEventBridgePaginatorsMockRecorder) NewListTargetsByRulePaginator(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListTargetsByRulePaginator", reflect.TypeOf((*MockEventBridgePaginators)(nil).NewListTargetsByRulePaginator), params)
+}
+
+// MockEventBridgeListRulesPaginator is a mock of EventBridgeListRulesPaginator interface.
+type MockEventBridgeListRulesPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventBridgeListRulesPaginatorMockRecorder
+}
+
+// MockEventBridgeListRulesPaginatorMockRecorder is the mock recorder for MockEventBridgeListRulesPaginator.
+type MockEventBridgeListRulesPaginatorMockRecorder struct {
+	mock *MockEventBridgeListRulesPaginator
+}
+
+// NewMockEventBridgeListRulesPaginator creates a new mock instance.
+func NewMockEventBridgeListRulesPaginator(ctrl *gomock.Controller) *MockEventBridgeListRulesPaginator {
+	mock := &MockEventBridgeListRulesPaginator{ctrl: ctrl}
+	mock.recorder = &MockEventBridgeListRulesPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventBridgeListRulesPaginator) EXPECT() *MockEventBridgeListRulesPaginatorMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockEventBridgeListRulesPaginator) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockEventBridgeListRulesPaginatorMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockEventBridgeListRulesPaginator)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockEventBridgeListRulesPaginator) NextPage(ctx context.Context, optFns ...func(*eventbridge.Options)) (*eventbridge.ListRulesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*eventbridge.ListRulesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockEventBridgeListRulesPaginatorMockRecorder) NextPage(ctx interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockEventBridgeListRulesPaginator)(nil).NextPage), varargs...)
+}
+
+// MockEventBridgeListTargetsByRulePaginator is a mock of EventBridgeListTargetsByRulePaginator interface.
+type MockEventBridgeListTargetsByRulePaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventBridgeListTargetsByRulePaginatorMockRecorder
+}
+
+// MockEventBridgeListTargetsByRulePaginatorMockRecorder is the mock recorder for MockEventBridgeListTargetsByRulePaginator.
+type MockEventBridgeListTargetsByRulePaginatorMockRecorder struct {
+	mock *MockEventBridgeListTargetsByRulePaginator
+}
+
+// NewMockEventBridgeListTargetsByRulePaginator creates a new mock instance.
+func NewMockEventBridgeListTargetsByRulePaginator(ctrl *gomock.Controller) *MockEventBridgeListTargetsByRulePaginator {
+	mock := &MockEventBridgeListTargetsByRulePaginator{ctrl: ctrl}
+	mock.recorder = &MockEventBridgeListTargetsByRulePaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventBridgeListTargetsByRulePaginator) EXPECT() *MockEventBridgeListTargetsByRulePaginatorMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockEventBridgeListTargetsByRulePaginator) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockEventBridgeListTargetsByRulePaginatorMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockEventBridgeListTargetsByRulePaginator)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockEventBridgeListTargetsByRulePaginator) NextPage(ctx context.Context, optFns ...func(*eventbridge.Options)) (*eventbridge.ListTargetsByRuleOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*eventbridge.ListTargetsByRuleOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockEventBridgeListTargetsByRulePaginatorMockRecorder) NextPage(ctx interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockEventBridgeListTargetsByRulePaginator)(nil).NextPage), varargs...)
+}
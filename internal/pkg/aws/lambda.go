@@ -16,6 +16,9 @@ type LambdaClient interface {
 
 type LambdaPaginators interface {
 	NewListFunctionsPaginator(params *lambda.ListFunctionsInput, optFns ...func(*lambda.ListFunctionsPaginatorOptions)) LambdaListFunctionsPaginator
+	NewListVersionsByFunctionPaginator(params *lambda.ListVersionsByFunctionInput, optFns ...func(*lambda.ListVersionsByFunctionPaginatorOptions)) LambdaListVersionsByFunctionPaginator
+	NewListAliasesPaginator(params *lambda.ListAliasesInput, optFns ...func(*lambda.ListAliasesPaginatorOptions)) LambdaListAliasesPaginator
+	NewListProvisionedConcurrencyConfigsPaginator(params *lambda.ListProvisionedConcurrencyConfigsInput, optFns ...func(*lambda.ListProvisionedConcurrencyConfigsPaginatorOptions)) LambdaListProvisionedConcurrencyConfigsPaginator
 }
 
 type lambdaPaginators struct {
@@ -30,3 +33,30 @@ type LambdaListFunctionsPaginator interface {
 	HasMorePages() bool
 	NextPage(ctx context.Context, optFns ...func(*lambda.Options)) (*lambda.ListFunctionsOutput, error)
 }
+
+func (l *lambdaPaginators) NewListVersionsByFunctionPaginator(params *lambda.ListVersionsByFunctionInput, optFns ...func(*lambda.ListVersionsByFunctionPaginatorOptions)) LambdaListVersionsByFunctionPaginator {
+	return lambda.NewListVersionsByFunctionPaginator(l.client, params, optFns...)
+}
+
+type LambdaListVersionsByFunctionPaginator interface {
+	HasMorePages() bool
+	NextPage(ctx context.Context, optFns ...func(*lambda.Options)) (*lambda.ListVersionsByFunctionOutput, error)
+}
+
+func (l *lambdaPaginators) NewListAliasesPaginator(params *lambda.ListAliasesInput, optFns ...func(*lambda.ListAliasesPaginatorOptions)) LambdaListAliasesPaginator {
+	return lambda.NewListAliasesPaginator(l.client, params, optFns...)
+}
+
+type LambdaListAliasesPaginator interface {
+	HasMorePages() bool
+	NextPage(ctx context.Context, optFns ...func(*lambda.Options)) (*lambda.ListAliasesOutput, error)
+}
+
+func (l *lambdaPaginators) NewListProvisionedConcurrencyConfigsPaginator(params *lambda.ListProvisionedConcurrencyConfigsInput, optFns ...func(*lambda.ListProvisionedConcurrencyConfigsPaginatorOptions)) LambdaListProvisionedConcurrencyConfigsPaginator {
+	return lambda.NewListProvisionedConcurrencyConfigsPaginator(l.client, params, optFns...)
+}
+
+type LambdaListProvisionedConcurrencyConfigsPaginator interface {
+	HasMorePages() bool
+	NextPage(ctx context.Context, optFns ...func(*lambda.Options)) (*lambda.ListProvisionedConcurrencyConfigsOutput, error)
+}
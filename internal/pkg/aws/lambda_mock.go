@@ -78,6 +78,25 @@ func (m *MockLambdaPaginators) EXPECT() *MockLambdaPaginatorsMockRecorder {
 	return m.recorder
 }
 
+// NewListAliasesPaginator mocks base method.
+func (m *MockLambdaPaginators) NewListAliasesPaginator(params *lambda.ListAliasesInput, optFns ...func(*lambda.ListAliasesPaginatorOptions)) LambdaListAliasesPaginator {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListAliasesPaginator", varargs...)
+	ret0, _ := ret[0].(LambdaListAliasesPaginator)
+	return ret0
+}
+
+// NewListAliasesPaginator indicates an expected call of NewListAliasesPaginator.
+func (mr *MockLambdaPaginatorsMockRecorder) NewListAliasesPaginator(params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListAliasesPaginator", reflect.TypeOf((*MockLambdaPaginators)(nil).NewListAliasesPaginator), varargs...)
+}
+
 // NewListFunctionsPaginator mocks base method.
 func (m *MockLambdaPaginators) NewListFunctionsPaginator(params *lambda.ListFunctionsInput, optFns ...func(*lambda.ListFunctionsPaginatorOptions)) LambdaListFunctionsPaginator {
 	m.ctrl.T.Helper()
@@ -97,6 +116,44 @@ func (mr *MockLambdaPaginatorsMockRecorder) NewListFunctionsPaginator(params int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListFunctionsPaginator", reflect.TypeOf((*MockLambdaPaginators)(nil).NewListFunctionsPaginator), varargs...)
 }
 
+// NewListProvisionedConcurrencyConfigsPaginator mocks base method.
+func (m *MockLambdaPaginators) NewListProvisionedConcurrencyConfigsPaginator(params *lambda.ListProvisionedConcurrencyConfigsInput, optFns ...func(*lambda.ListProvisionedConcurrencyConfigsPaginatorOptions)) LambdaListProvisionedConcurrencyConfigsPaginator {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListProvisionedConcurrencyConfigsPaginator", varargs...)
+	ret0, _ := ret[0].(LambdaListProvisionedConcurrencyConfigsPaginator)
+	return ret0
+}
+
+// NewListProvisionedConcurrencyConfigsPaginator indicates an expected call of NewListProvisionedConcurrencyConfigsPaginator.
+func (mr *MockLambdaPaginatorsMockRecorder) NewListProvisionedConcurrencyConfigsPaginator(params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListProvisionedConcurrencyConfigsPaginator", reflect.TypeOf((*MockLambdaPaginators)(nil).NewListProvisionedConcurrencyConfigsPaginator), varargs...)
+}
+
+// NewListVersionsByFunctionPaginator mocks base method.
+func (m *MockLambdaPaginators) NewListVersionsByFunctionPaginator(params *lambda.ListVersionsByFunctionInput, optFns ...func(*lambda.ListVersionsByFunctionPaginatorOptions)) LambdaListVersionsByFunctionPaginator {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListVersionsByFunctionPaginator", varargs...)
+	ret0, _ := ret[0].(LambdaListVersionsByFunctionPaginator)
+	return ret0
+}
+
+// NewListVersionsByFunctionPaginator indicates an expected call of NewListVersionsByFunctionPaginator.
+func (mr *MockLambdaPaginatorsMockRecorder) NewListVersionsByFunctionPaginator(params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListVersionsByFunctionPaginator", reflect.TypeOf((*MockLambdaPaginators)(nil).NewListVersionsByFunctionPaginator), varargs...)
+}
+
 // MockLambdaListFunctionsPaginator is a mock of LambdaListFunctionsPaginator interface.
 type MockLambdaListFunctionsPaginator struct {
 	ctrl     *gomock.Controller
@@ -153,3 +210,174 @@ func (mr *MockLambdaListFunctionsPaginatorMockRecorder) NextPage(ctx interface{}
 	varargs := append([]interface{}{ctx}, optFns...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockLambdaListFunctionsPaginator)(nil).NextPage), varargs...)
 }
+
+// MockLambdaListVersionsByFunctionPaginator is a mock of LambdaListVersionsByFunctionPaginator interface.
+type MockLambdaListVersionsByFunctionPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockLambdaListVersionsByFunctionPaginatorMockRecorder
+}
+
+// MockLambdaListVersionsByFunctionPaginatorMockRecorder is the mock recorder for MockLambdaListVersionsByFunctionPaginator.
+type MockLambdaListVersionsByFunctionPaginatorMockRecorder struct {
+	mock *MockLambdaListVersionsByFunctionPaginator
+}
+
+// NewMockLambdaListVersionsByFunctionPaginator creates a new mock instance.
+func NewMockLambdaListVersionsByFunctionPaginator(ctrl *gomock.Controller) *MockLambdaListVersionsByFunctionPaginator {
+	mock := &MockLambdaListVersionsByFunctionPaginator{ctrl: ctrl}
+	mock.recorder = &MockLambdaListVersionsByFunctionPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLambdaListVersionsByFunctionPaginator) EXPECT() *MockLambdaListVersionsByFunctionPaginatorMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockLambdaListVersionsByFunctionPaginator) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockLambdaListVersionsByFunctionPaginatorMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockLambdaListVersionsByFunctionPaginator)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockLambdaListVersionsByFunctionPaginator) NextPage(ctx context.Context, optFns ...func(*lambda.Options)) (*lambda.ListVersionsByFunctionOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*lambda.ListVersionsByFunctionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockLambdaListVersionsByFunctionPaginatorMockRecorder) NextPage(ctx interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockLambdaListVersionsByFunctionPaginator)(nil).NextPage), varargs...)
+}
+
+// MockLambdaListAliasesPaginator is a mock of LambdaListAliasesPaginator interface.
+type MockLambdaListAliasesPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockLambdaListAliasesPaginatorMockRecorder
+}
+
+// MockLambdaListAliasesPaginatorMockRecorder is the mock recorder for MockLambdaListAliasesPaginator.
+type MockLambdaListAliasesPaginatorMockRecorder struct {
+	mock *MockLambdaListAliasesPaginator
+}
+
+// NewMockLambdaListAliasesPaginator creates a new mock instance.
+func NewMockLambdaListAliasesPaginator(ctrl *gomock.Controller) *MockLambdaListAliasesPaginator {
+	mock := &MockLambdaListAliasesPaginator{ctrl: ctrl}
+	mock.recorder = &MockLambdaListAliasesPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLambdaListAliasesPaginator) EXPECT() *MockLambdaListAliasesPaginatorMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockLambdaListAliasesPaginator) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockLambdaListAliasesPaginatorMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockLambdaListAliasesPaginator)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockLambdaListAliasesPaginator) NextPage(ctx context.Context, optFns ...func(*lambda.Options)) (*lambda.ListAliasesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*lambda.ListAliasesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockLambdaListAliasesPaginatorMockRecorder) NextPage(ctx interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockLambdaListAliasesPaginator)(nil).NextPage), varargs...)
+}
+
+// MockLambdaListProvisionedConcurrencyConfigsPaginator is a mock of LambdaListProvisionedConcurrencyConfigsPaginator interface.
+type MockLambdaListProvisionedConcurrencyConfigsPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockLambdaListProvisionedConcurrencyConfigsPaginatorMockRecorder
+}
+
+// MockLambdaListProvisionedConcurrencyConfigsPaginatorMockRecorder is the mock recorder for MockLambdaListProvisionedConcurrencyConfigsPaginator.
+type MockLambdaListProvisionedConcurrencyConfigsPaginatorMockRecorder struct {
+	mock *MockLambdaListProvisionedConcurrencyConfigsPaginator
+}
+
+// NewMockLambdaListProvisionedConcurrencyConfigsPaginator creates a new mock instance.
+func NewMockLambdaListProvisionedConcurrencyConfigsPaginator(ctrl *gomock.Controller) *MockLambdaListProvisionedConcurrencyConfigsPaginator {
+	mock := &MockLambdaListProvisionedConcurrencyConfigsPaginator{ctrl: ctrl}
+	mock.recorder = &MockLambdaListProvisionedConcurrencyConfigsPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLambdaListProvisionedConcurrencyConfigsPaginator) EXPECT() *MockLambdaListProvisionedConcurrencyConfigsPaginatorMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockLambdaListProvisionedConcurrencyConfigsPaginator) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockLambdaListProvisionedConcurrencyConfigsPaginatorMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockLambdaListProvisionedConcurrencyConfigsPaginator)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockLambdaListProvisionedConcurrencyConfigsPaginator) NextPage(ctx context.Context, optFns ...func(*lambda.Options)) (*lambda.ListProvisionedConcurrencyConfigsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*lambda.ListProvisionedConcurrencyConfigsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockLambdaListProvisionedConcurrencyConfigsPaginatorMockRecorder) NextPage(ctx interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockLambdaListProvisionedConcurrencyConfigsPaginator)(nil).NextPage), varargs...)
+}
@@ -11,44 +11,96 @@ func NewMockProvider(ctrl *gomock.Controller) *MockProvider {
 	mockAppRunnerPaginators := NewMockAppRunnerPaginators(ctrl)
 	mockEcrClient := NewMockEcrClient(ctrl)
 	mockEcrPaginators := NewMockEcrPaginators(ctrl)
+	mockSsmClient := NewMockSsmClient(ctrl)
 	mockSsmPaginators := NewMockSsmPaginators(ctrl)
+	mockEventBridgePaginators := NewMockEventBridgePaginators(ctrl)
+	mockSchedulerClient := NewMockSchedulerClient(ctrl)
+	mockSchedulerPaginators := NewMockSchedulerPaginators(ctrl)
+	mockEksClient := NewMockEksClient(ctrl)
+	mockStsPresignClient := NewMockStsPresignClient(ctrl)
+	mockBatchClient := NewMockBatchClient(ctrl)
+	mockBatchPaginators := NewMockBatchPaginators(ctrl)
+	mockSageMakerClient := NewMockSageMakerClient(ctrl)
+	mockSageMakerPaginators := NewMockSageMakerPaginators(ctrl)
+	mockCodeBuildClient := NewMockCodeBuildClient(ctrl)
+	mockCodeBuildPaginators := NewMockCodeBuildPaginators(ctrl)
+	mockS3Client := NewMockS3Client(ctrl)
 
 	return &MockProvider{
 		Provider: &Provider{
 			Region: "mock-aws-region",
 
-			EcsClient:           mockEcsClient,
-			EcsPaginators:       mockEcsPaginators,
-			LambdaClient:        mockLambdaClient,
-			LambdaPaginators:    mockLambdaPaginators,
-			AppRunnerClient:     mockAppRunnerClient,
-			AppRunnerPaginators: mockAppRunnerPaginators,
-			EcrClient:           mockEcrClient,
-			EcrPaginators:       mockEcrPaginators,
-			SsmPaginators:       mockSsmPaginators,
+			EcsClient:             mockEcsClient,
+			EcsPaginators:         mockEcsPaginators,
+			LambdaClient:          mockLambdaClient,
+			LambdaPaginators:      mockLambdaPaginators,
+			AppRunnerClient:       mockAppRunnerClient,
+			AppRunnerPaginators:   mockAppRunnerPaginators,
+			EcrClient:             mockEcrClient,
+			EcrPaginators:         mockEcrPaginators,
+			SsmClient:             mockSsmClient,
+			SsmPaginators:         mockSsmPaginators,
+			EventBridgePaginators: mockEventBridgePaginators,
+			SchedulerClient:       mockSchedulerClient,
+			SchedulerPaginators:   mockSchedulerPaginators,
+			EksClient:             mockEksClient,
+			StsPresignClient:      mockStsPresignClient,
+			BatchClient:           mockBatchClient,
+			BatchPaginators:       mockBatchPaginators,
+			SageMakerClient:       mockSageMakerClient,
+			SageMakerPaginators:   mockSageMakerPaginators,
+			CodeBuildClient:       mockCodeBuildClient,
+			CodeBuildPaginators:   mockCodeBuildPaginators,
+			S3Client:              mockS3Client,
 		},
-		MockEcsClient:           mockEcsClient,
-		MockEcsPaginators:       mockEcsPaginators,
-		MockLambdaClient:        mockLambdaClient,
-		MockLambdaPaginators:    mockLambdaPaginators,
-		MockAppRunnerClient:     mockAppRunnerClient,
-		MockAppRunnerPaginators: mockAppRunnerPaginators,
-		MockEcrClient:           mockEcrClient,
-		MockEcrPaginators:       mockEcrPaginators,
-		MockSsmPaginators:       mockSsmPaginators,
+		MockEcsClient:             mockEcsClient,
+		MockEcsPaginators:         mockEcsPaginators,
+		MockLambdaClient:          mockLambdaClient,
+		MockLambdaPaginators:      mockLambdaPaginators,
+		MockAppRunnerClient:       mockAppRunnerClient,
+		MockAppRunnerPaginators:   mockAppRunnerPaginators,
+		MockEcrClient:             mockEcrClient,
+		MockEcrPaginators:         mockEcrPaginators,
+		MockSsmClient:             mockSsmClient,
+		MockSsmPaginators:         mockSsmPaginators,
+		MockEventBridgePaginators: mockEventBridgePaginators,
+		MockSchedulerClient:       mockSchedulerClient,
+		MockSchedulerPaginators:   mockSchedulerPaginators,
+		MockEksClient:             mockEksClient,
+		MockStsPresignClient:      mockStsPresignClient,
+		MockBatchClient:           mockBatchClient,
+		MockBatchPaginators:       mockBatchPaginators,
+		MockSageMakerClient:       mockSageMakerClient,
+		MockSageMakerPaginators:   mockSageMakerPaginators,
+		MockCodeBuildClient:       mockCodeBuildClient,
+		MockCodeBuildPaginators:   mockCodeBuildPaginators,
+		MockS3Client:              mockS3Client,
 	}
 }
 
 type MockProvider struct {
 	Provider *Provider
 
-	MockEcsClient           *MockEcsClient
-	MockEcsPaginators       *MockEcsPaginators
-	MockLambdaClient        *MockLambdaClient
-	MockLambdaPaginators    *MockLambdaPaginators
-	MockAppRunnerClient     *MockAppRunnerClient
-	MockAppRunnerPaginators *MockAppRunnerPaginators
-	MockEcrClient           *MockEcrClient
-	MockEcrPaginators       *MockEcrPaginators
-	MockSsmPaginators       *MockSsmPaginators
+	MockEcsClient             *MockEcsClient
+	MockEcsPaginators         *MockEcsPaginators
+	MockLambdaClient          *MockLambdaClient
+	MockLambdaPaginators      *MockLambdaPaginators
+	MockAppRunnerClient       *MockAppRunnerClient
+	MockAppRunnerPaginators   *MockAppRunnerPaginators
+	MockEcrClient             *MockEcrClient
+	MockEcrPaginators         *MockEcrPaginators
+	MockSsmClient             *MockSsmClient
+	MockSsmPaginators         *MockSsmPaginators
+	MockEventBridgePaginators *MockEventBridgePaginators
+	MockSchedulerClient       *MockSchedulerClient
+	MockSchedulerPaginators   *MockSchedulerPaginators
+	MockEksClient             *MockEksClient
+	MockStsPresignClient      *MockStsPresignClient
+	MockBatchClient           *MockBatchClient
+	MockBatchPaginators       *MockBatchPaginators
+	MockSageMakerClient       *MockSageMakerClient
+	MockSageMakerPaginators   *MockSageMakerPaginators
+	MockCodeBuildClient       *MockCodeBuildClient
+	MockCodeBuildPaginators   *MockCodeBuildPaginators
+	MockS3Client              *MockS3Client
 }
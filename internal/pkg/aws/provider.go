@@ -2,39 +2,108 @@ package aws
 
 import (
 	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	gerrors "github.com/pkg/errors"
 )
 
-func NewProvider() (*Provider, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO())
+// NewProvider creates a provider with the default credentials and region, all its clients use retryConfig.
+func NewProvider(ctx context.Context, retryConfig RetryConfig) (*Provider, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
 	if err != nil {
 		return nil, gerrors.Wrapf(err, "cannot load aws config")
 	}
+	cfg.Retryer = newRetryer(retryConfig)
 
+	return NewProviderForConfig(cfg), nil
+}
+
+// ForRegion returns a provider with the same credentials, but with clients for a different region.
+func (p *Provider) ForRegion(region string) *Provider {
+	cfg := p.cfg.Copy()
+	cfg.Region = region
+
+	provider := NewProviderForConfig(cfg)
+	provider.RoleArn = p.RoleArn
+	return provider
+}
+
+const assumedRoleSessionName = "aws-ecr-cleaner"
+
+// ForRole returns a provider using credentials of the assumed role (usually in a different account), externalId is
+// optional. The role is assumed immediately, so an error is returned if it cannot be assumed.
+func (p *Provider) ForRole(ctx context.Context, roleArn string, externalId string) (*Provider, error) {
+	assumeRoleProvider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(p.cfg), roleArn, func(options *stscreds.AssumeRoleOptions) {
+		options.RoleSessionName = assumedRoleSessionName
+		if externalId != "" {
+			options.ExternalID = aws.String(externalId)
+		}
+	})
+
+	cfg := p.cfg.Copy()
+	cfg.Credentials = aws.NewCredentialsCache(assumeRoleProvider)
+
+	_, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, gerrors.Wrapf(err, "cannot assume role %v", roleArn)
+	}
+
+	provider := NewProviderForConfig(cfg)
+	provider.RoleArn = roleArn
+	return provider, nil
+}
+
+func NewProviderForConfig(cfg aws.Config) *Provider {
 	ecsClient := newEcsClient(cfg)
 	lambdaClient := newLambdaClient(cfg)
 	appRunnerClient := newAppRunnerClient(cfg)
 	ecrClient := newEcrClient(cfg)
 	ssmClient := newSsmClient(cfg)
+	eventBridgeClient := newEventBridgeClient(cfg)
+	schedulerClient := newSchedulerClient(cfg)
+	eksClient := newEksClient(cfg)
+	stsPresignClient := newStsPresignClient(cfg)
+	batchClient := newBatchClient(cfg)
+	sageMakerClient := newSageMakerClient(cfg)
+	codeBuildClient := newCodeBuildClient(cfg)
+	s3Client := newS3Client(cfg)
 
 	return &Provider{
 		Region: cfg.Region,
+		cfg:    cfg,
 
-		EcsClient:           ecsClient,
-		EcsPaginators:       &ecsPaginators{client: ecsClient},
-		LambdaClient:        lambdaClient,
-		LambdaPaginators:    &lambdaPaginators{client: lambdaClient},
-		AppRunnerClient:     appRunnerClient,
-		AppRunnerPaginators: &appRunnerPaginators{client: appRunnerClient},
-		EcrClient:           ecrClient,
-		EcrPaginators:       &ecrPaginators{client: ecrClient},
-		SsmPaginators:       &ssmPaginators{client: ssmClient},
-	}, nil
+		EcsClient:             ecsClient,
+		EcsPaginators:         &ecsPaginators{client: ecsClient},
+		LambdaClient:          lambdaClient,
+		LambdaPaginators:      &lambdaPaginators{client: lambdaClient},
+		AppRunnerClient:       appRunnerClient,
+		AppRunnerPaginators:   &appRunnerPaginators{client: appRunnerClient},
+		EcrClient:             ecrClient,
+		EcrPaginators:         &ecrPaginators{client: ecrClient},
+		SsmClient:             ssmClient,
+		SsmPaginators:         &ssmPaginators{client: ssmClient},
+		EventBridgePaginators: &eventBridgePaginators{client: eventBridgeClient},
+		SchedulerClient:       schedulerClient,
+		SchedulerPaginators:   &schedulerPaginators{client: schedulerClient},
+		EksClient:             eksClient,
+		StsPresignClient:      stsPresignClient,
+		BatchClient:           batchClient,
+		BatchPaginators:       &batchPaginators{client: batchClient},
+		SageMakerClient:       sageMakerClient,
+		SageMakerPaginators:   &sageMakerPaginators{client: sageMakerClient},
+		CodeBuildClient:       codeBuildClient,
+		CodeBuildPaginators:   &codeBuildPaginators{client: codeBuildClient},
+		S3Client:              s3Client,
+	}
 }
 
 type Provider struct {
 	Region string
+	// RoleArn is the assumed role, empty if the provider uses the default credentials
+	RoleArn string
+	cfg     aws.Config
 
 	EcsClient     EcsClient
 	EcsPaginators EcsPaginators
@@ -48,5 +117,26 @@ type Provider struct {
 	EcrClient     EcrClient
 	EcrPaginators EcrPaginators
 
+	SsmClient     SsmClient
 	SsmPaginators SsmPaginators
+
+	EventBridgePaginators EventBridgePaginators
+
+	SchedulerClient     SchedulerClient
+	SchedulerPaginators SchedulerPaginators
+
+	EksClient EksClient
+
+	StsPresignClient StsPresignClient
+
+	BatchClient     BatchClient
+	BatchPaginators BatchPaginators
+
+	SageMakerClient     SageMakerClient
+	SageMakerPaginators SageMakerPaginators
+
+	CodeBuildClient     CodeBuildClient
+	CodeBuildPaginators CodeBuildPaginators
+
+	S3Client S3Client
 }
@@ -0,0 +1,129 @@
+package aws
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestForRole(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		externalId          string
+		stsStatusCode       int
+		expectedExternalIds []string
+		expectedError       bool
+	}{
+		"Role assumed": {
+			externalId:          "",
+			stsStatusCode:       http.StatusOK,
+			expectedExternalIds: nil,
+			expectedError:       false,
+		},
+		"Role assumed with external id": {
+			externalId:          "externalId1",
+			stsStatusCode:       http.StatusOK,
+			expectedExternalIds: []string{"externalId1"},
+			expectedError:       false,
+		},
+		"Role cannot be assumed": {
+			externalId:          "externalId1",
+			stsStatusCode:       http.StatusForbidden,
+			expectedExternalIds: []string{"externalId1"},
+			expectedError:       true,
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			stsServer := newFakeStsServer(t, testCase.stsStatusCode, "role1Arn", testCase.expectedExternalIds)
+
+			provider := NewProviderForConfig(aws.Config{
+				Region:      "eu-west-1",
+				Credentials: credentials.NewStaticCredentialsProvider("accessKeyId", "secretAccessKey", ""),
+				EndpointResolverWithOptions: aws.EndpointResolverWithOptionsFunc(
+					func(service, region string, options ...interface{}) (aws.Endpoint, error) {
+						return aws.Endpoint{URL: stsServer.URL}, nil
+					}),
+				Retryer: func() aws.Retryer {
+					return aws.NopRetryer{}
+				},
+			})
+
+			roleProvider, err := provider.ForRole(context.TODO(), "role1Arn", testCase.externalId)
+			if testCase.expectedError {
+				if err == nil {
+					t.Error("Expected error when the role cannot be assumed")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			regionRoleProvider := roleProvider.ForRegion("us-east-1")
+			if regionRoleProvider.RoleArn != "role1Arn" || regionRoleProvider.Region != "us-east-1" {
+				t.Errorf("Unexpected provider for region %v with role %v", regionRoleProvider.Region, regionRoleProvider.RoleArn)
+			}
+
+			roleCredentials, err := regionRoleProvider.cfg.Credentials.Retrieve(context.TODO())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if roleCredentials.AccessKeyID != "assumedAccessKeyId" {
+				t.Errorf("Unexpected credentials %v", roleCredentials.AccessKeyID)
+			}
+		})
+	}
+}
+
+// newFakeStsServer starts a fake STS server responding to AssumeRole requests for the expected role.
+func newFakeStsServer(t *testing.T, statusCode int, expectedRoleArn string, expectedExternalIds []string) *httptest.Server {
+	stsServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		err := request.ParseForm()
+		if err != nil {
+			t.Error(err)
+		}
+		if request.Form.Get("Action") != "AssumeRole" || request.Form.Get("RoleArn") != expectedRoleArn {
+			t.Errorf("Unexpected STS request %v", request.Form)
+		}
+		externalIds := request.Form["ExternalId"]
+		if len(externalIds) != len(expectedExternalIds) || (len(externalIds) > 0 && externalIds[0] != expectedExternalIds[0]) {
+			t.Errorf("Unexpected external ids %v", externalIds)
+		}
+
+		writer.Header().Set("Content-Type", "text/xml")
+		writer.WriteHeader(statusCode)
+		if statusCode != http.StatusOK {
+			_, err = writer.Write([]byte(`<ErrorResponse>
+				<Error><Type>Sender</Type><Code>AccessDenied</Code><Message>Access denied</Message></Error>
+			</ErrorResponse>`))
+		} else {
+			_, err = writer.Write([]byte(`<AssumeRoleResponse>
+				<AssumeRoleResult>
+					<Credentials>
+						<AccessKeyId>assumedAccessKeyId</AccessKeyId>
+						<SecretAccessKey>assumedSecretAccessKey</SecretAccessKey>
+						<SessionToken>assumedSessionToken</SessionToken>
+						<Expiration>` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `</Expiration>
+					</Credentials>
+				</AssumeRoleResult>
+			</AssumeRoleResponse>`))
+		}
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(stsServer.Close)
+
+	return stsServer
+}
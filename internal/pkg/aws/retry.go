@@ -0,0 +1,51 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"time"
+)
+
+// RetryConfig configures retries of all AWS clients created by a provider. Every client gets its own retryer (and its
+// own token buckets), so throttling of one service (or one region or account) does not slow down calls to the others.
+type RetryConfig struct {
+	// Mode is aws.RetryModeAdaptive (client-side rate limiting after throttling errors) or aws.RetryModeStandard
+	Mode aws.RetryMode
+	// MaxAttempts is the maximum number of attempts of a single call, including the first one
+	MaxAttempts int
+	// MaxBackoff caps the delay between attempts
+	MaxBackoff time.Duration
+	// RetryTokens is the size of the token bucket retries are taken from, every retry costs retry.DefaultRetryCost
+	// tokens (retry.DefaultRetryTimeoutCost after a timeout) and every successful call returns some of them
+	RetryTokens uint
+}
+
+// DefaultRetryConfig returns retry settings suitable for large registries, calls are retried longer than by default and
+// the rate of calls is adapted to throttling errors.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		Mode:        aws.RetryModeAdaptive,
+		MaxAttempts: 10,
+		MaxBackoff:  20 * time.Second,
+		RetryTokens: retry.DefaultRetryRateTokens,
+	}
+}
+
+// newRetryer returns a function creating a new retryer for every client.
+func newRetryer(retryConfig RetryConfig) func() aws.Retryer {
+	return func() aws.Retryer {
+		standardOptions := func(options *retry.StandardOptions) {
+			options.MaxAttempts = retryConfig.MaxAttempts
+			options.MaxBackoff = retryConfig.MaxBackoff
+			options.RateLimiter = ratelimit.NewTokenRateLimit(retryConfig.RetryTokens)
+		}
+
+		if retryConfig.Mode == aws.RetryModeAdaptive {
+			return retry.NewAdaptiveMode(func(options *retry.AdaptiveModeOptions) {
+				options.StandardOptions = append(options.StandardOptions, standardOptions)
+			})
+		}
+		return retry.NewStandard(standardOptions)
+	}
+}
@@ -0,0 +1,118 @@
+package aws
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryer(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		retryConfig      RetryConfig
+		throttledCalls   int32
+		expectedRequests int32
+		expectedError    bool
+	}{
+		"Throttling burst retried in standard mode": {
+			retryConfig: RetryConfig{
+				Mode:        aws.RetryModeStandard,
+				MaxAttempts: 5,
+				MaxBackoff:  time.Millisecond,
+				RetryTokens: 500,
+			},
+			throttledCalls:   4,
+			expectedRequests: 5,
+		},
+		"Throttling burst retried in adaptive mode": {
+			retryConfig: RetryConfig{
+				Mode:        aws.RetryModeAdaptive,
+				MaxAttempts: 5,
+				MaxBackoff:  time.Millisecond,
+				RetryTokens: 500,
+			},
+			throttledCalls:   1,
+			expectedRequests: 2,
+		},
+		"Max attempts exceeded": {
+			retryConfig: RetryConfig{
+				Mode:        aws.RetryModeStandard,
+				MaxAttempts: 3,
+				MaxBackoff:  time.Millisecond,
+				RetryTokens: 500,
+			},
+			throttledCalls:   5,
+			expectedRequests: 3,
+			expectedError:    true,
+		},
+		"Retry tokens exhausted": {
+			retryConfig: RetryConfig{
+				Mode:        aws.RetryModeStandard,
+				MaxAttempts: 5,
+				MaxBackoff:  time.Millisecond,
+				RetryTokens: 5,
+			},
+			throttledCalls:   5,
+			expectedRequests: 2,
+			expectedError:    true,
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests int32
+			ecrServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				writer.Header().Set("Content-Type", "application/x-amz-json-1.1")
+				if atomic.AddInt32(&requests, 1) <= testCase.throttledCalls {
+					writer.WriteHeader(http.StatusBadRequest)
+					_, err := writer.Write([]byte(`{"__type": "ThrottlingException", "message": "Rate exceeded"}`))
+					if err != nil {
+						t.Error(err)
+					}
+					return
+				}
+				_, err := writer.Write([]byte(`{"tags": []}`))
+				if err != nil {
+					t.Error(err)
+				}
+			}))
+			t.Cleanup(ecrServer.Close)
+
+			provider := NewProviderForConfig(aws.Config{
+				Region:      "eu-west-1",
+				Credentials: credentials.NewStaticCredentialsProvider("accessKeyId", "secretAccessKey", ""),
+				EndpointResolverWithOptions: aws.EndpointResolverWithOptionsFunc(
+					func(service, region string, options ...interface{}) (aws.Endpoint, error) {
+						return aws.Endpoint{URL: ecrServer.URL}, nil
+					}),
+				Retryer: newRetryer(testCase.retryConfig),
+			})
+
+			_, err := provider.EcrClient.ListTagsForResource(context.TODO(), &ecr.ListTagsForResourceInput{
+				ResourceArn: aws.String("repo1Arn"),
+			})
+			if testCase.expectedError {
+				if err == nil {
+					t.Error("Expected error")
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if atomic.LoadInt32(&requests) != testCase.expectedRequests {
+				t.Errorf("Requests %v different than expected %v", requests, testCase.expectedRequests)
+			}
+		})
+	}
+}
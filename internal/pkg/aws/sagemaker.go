@@ -0,0 +1,65 @@
+package aws
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sagemaker"
+)
+
+func newSageMakerClient(cfg aws.Config) *sagemaker.Client {
+	return sagemaker.NewFromConfig(cfg)
+}
+
+type SageMakerClient interface {
+	DescribeModel(ctx context.Context, params *sagemaker.DescribeModelInput, optFns ...func(*sagemaker.Options)) (*sagemaker.DescribeModelOutput, error)
+	DescribeEndpoint(ctx context.Context, params *sagemaker.DescribeEndpointInput, optFns ...func(*sagemaker.Options)) (*sagemaker.DescribeEndpointOutput, error)
+	DescribeTrainingJob(ctx context.Context, params *sagemaker.DescribeTrainingJobInput, optFns ...func(*sagemaker.Options)) (*sagemaker.DescribeTrainingJobOutput, error)
+	DescribeProcessingJob(ctx context.Context, params *sagemaker.DescribeProcessingJobInput, optFns ...func(*sagemaker.Options)) (*sagemaker.DescribeProcessingJobOutput, error)
+}
+
+type SageMakerPaginators interface {
+	NewListModelsPaginator(params *sagemaker.ListModelsInput, optFns ...func(*sagemaker.ListModelsPaginatorOptions)) SageMakerListModelsPaginator
+	NewListEndpointsPaginator(params *sagemaker.ListEndpointsInput, optFns ...func(*sagemaker.ListEndpointsPaginatorOptions)) SageMakerListEndpointsPaginator
+	NewListTrainingJobsPaginator(params *sagemaker.ListTrainingJobsInput, optFns ...func(*sagemaker.ListTrainingJobsPaginatorOptions)) SageMakerListTrainingJobsPaginator
+	NewListProcessingJobsPaginator(params *sagemaker.ListProcessingJobsInput, optFns ...func(*sagemaker.ListProcessingJobsPaginatorOptions)) SageMakerListProcessingJobsPaginator
+}
+
+type sageMakerPaginators struct {
+	client *sagemaker.Client
+}
+
+func (s *sageMakerPaginators) NewListModelsPaginator(params *sagemaker.ListModelsInput, optFns ...func(*sagemaker.ListModelsPaginatorOptions)) SageMakerListModelsPaginator {
+	return sagemaker.NewListModelsPaginator(s.client, params, optFns...)
+}
+
+type SageMakerListModelsPaginator interface {
+	HasMorePages() bool
+	NextPage(ctx context.Context, optFns ...func(*sagemaker.Options)) (*sagemaker.ListModelsOutput, error)
+}
+
+func (s *sageMakerPaginators) NewListEndpointsPaginator(params *sagemaker.ListEndpointsInput, optFns ...func(*sagemaker.ListEndpointsPaginatorOptions)) SageMakerListEndpointsPaginator {
+	return sagemaker.NewListEndpointsPaginator(s.client, params, optFns...)
+}
+
+type SageMakerListEndpointsPaginator interface {
+	HasMorePages() bool
+	NextPage(ctx context.Context, optFns ...func(*sagemaker.Options)) (*sagemaker.ListEndpointsOutput, error)
+}
+
+func (s *sageMakerPaginators) NewListTrainingJobsPaginator(params *sagemaker.ListTrainingJobsInput, optFns ...func(*sagemaker.ListTrainingJobsPaginatorOptions)) SageMakerListTrainingJobsPaginator {
+	return sagemaker.NewListTrainingJobsPaginator(s.client, params, optFns...)
+}
+
+type SageMakerListTrainingJobsPaginator interface {
+	HasMorePages() bool
+	NextPage(ctx context.Context, optFns ...func(*sagemaker.Options)) (*sagemaker.ListTrainingJobsOutput, error)
+}
+
+func (s *sageMakerPaginators) NewListProcessingJobsPaginator(params *sagemaker.ListProcessingJobsInput, optFns ...func(*sagemaker.ListProcessingJobsPaginatorOptions)) SageMakerListProcessingJobsPaginator {
+	return sagemaker.NewListProcessingJobsPaginator(s.client, params, optFns...)
+}
+
+type SageMakerListProcessingJobsPaginator interface {
+	HasMorePages() bool
+	NextPage(ctx context.Context, optFns ...func(*sagemaker.Options)) (*sagemaker.ListProcessingJobsOutput, error)
+}
@@ -0,0 +1,443 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/pkg/aws/sagemaker.go
+
+// Package aws is a generated GoMock package.
+package aws
+
+import (
+	context "context"
+	reflect "reflect"
+
+	sagemaker "github.com/aws/aws-sdk-go-v2/service/sagemaker"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSageMakerClient is a mock of SageMakerClient interface.
+type MockSageMakerClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockSageMakerClientMockRecorder
+}
+
+// MockSageMakerClientMockRecorder is the mock recorder for MockSageMakerClient.
+type MockSageMakerClientMockRecorder struct {
+	mock *MockSageMakerClient
+}
+
+// NewMockSageMakerClient creates a new mock instance.
+func NewMockSageMakerClient(ctrl *gomock.Controller) *MockSageMakerClient {
+	mock := &MockSageMakerClient{ctrl: ctrl}
+	mock.recorder = &MockSageMakerClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSageMakerClient) EXPECT() *MockSageMakerClientMockRecorder {
+	return m.recorder
+}
+
+// DescribeEndpoint mocks base method.
+func (m *MockSageMakerClient) DescribeEndpoint(ctx context.Context, params *sagemaker.DescribeEndpointInput, optFns ...func(*sagemaker.Options)) (*sagemaker.DescribeEndpointOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeEndpoint", varargs...)
+	ret0, _ := ret[0].(*sagemaker.DescribeEndpointOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeEndpoint indicates an expected call of DescribeEndpoint.
+func (mr *MockSageMakerClientMockRecorder) DescribeEndpoint(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeEndpoint", reflect.TypeOf((*MockSageMakerClient)(nil).DescribeEndpoint), varargs...)
+}
+
+// DescribeModel mocks base method.
+func (m *MockSageMakerClient) DescribeModel(ctx context.Context, params *sagemaker.DescribeModelInput, optFns ...func(*sagemaker.Options)) (*sagemaker.DescribeModelOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeModel", varargs...)
+	ret0, _ := ret[0].(*sagemaker.DescribeModelOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeModel indicates an expected call of DescribeModel.
+func (mr *MockSageMakerClientMockRecorder) DescribeModel(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeModel", reflect.TypeOf((*MockSageMakerClient)(nil).DescribeModel), varargs...)
+}
+
+// DescribeProcessingJob mocks base method.
+func (m *MockSageMakerClient) DescribeProcessingJob(ctx context.Context, params *sagemaker.DescribeProcessingJobInput, optFns ...func(*sagemaker.Options)) (*sagemaker.DescribeProcessingJobOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeProcessingJob", varargs...)
+	ret0, _ := ret[0].(*sagemaker.DescribeProcessingJobOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeProcessingJob indicates an expected call of DescribeProcessingJob.
+func (mr *MockSageMakerClientMockRecorder) DescribeProcessingJob(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeProcessingJob", reflect.TypeOf((*MockSageMakerClient)(nil).DescribeProcessingJob), varargs...)
+}
+
+// DescribeTrainingJob mocks base method.
+func (m *MockSageMakerClient) DescribeTrainingJob(ctx context.Context, params *sagemaker.DescribeTrainingJobInput, optFns ...func(*sagemaker.Options)) (*sagemaker.DescribeTrainingJobOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeTrainingJob", varargs...)
+	ret0, _ := ret[0].(*sagemaker.DescribeTrainingJobOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeTrainingJob indicates an expected call of DescribeTrainingJob.
+func (mr *MockSageMakerClientMockRecorder) DescribeTrainingJob(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeTrainingJob", reflect.TypeOf((*MockSageMakerClient)(nil).DescribeTrainingJob), varargs...)
+}
+
+// MockSageMakerPaginators is a mock of SageMakerPaginators interface.
+type MockSageMakerPaginators struct {
+	ctrl     *gomock.Controller
+	recorder *MockSageMakerPaginatorsMockRecorder
+}
+
+// MockSageMakerPaginatorsMockRecorder is the mock recorder for MockSageMakerPaginators.
+type MockSageMakerPaginatorsMockRecorder struct {
+	mock *MockSageMakerPaginators
+}
+
+// NewMockSageMakerPaginators creates a new mock instance.
+func NewMockSageMakerPaginators(ctrl *gomock.Controller) *MockSageMakerPaginators {
+	mock := &MockSageMakerPaginators{ctrl: ctrl}
+	mock.recorder = &MockSageMakerPaginatorsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSageMakerPaginators) EXPECT() *MockSageMakerPaginatorsMockRecorder {
+	return m.recorder
+}
+
+// NewListEndpointsPaginator mocks base method.
+func (m *MockSageMakerPaginators) NewListEndpointsPaginator(params *sagemaker.ListEndpointsInput, optFns ...func(*sagemaker.ListEndpointsPaginatorOptions)) SageMakerListEndpointsPaginator {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListEndpointsPaginator", varargs...)
+	ret0, _ := ret[0].(SageMakerListEndpointsPaginator)
+	return ret0
+}
+
+// NewListEndpointsPaginator indicates an expected call of NewListEndpointsPaginator.
+func (mr *MockSageMakerPaginatorsMockRecorder) NewListEndpointsPaginator(params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListEndpointsPaginator", reflect.TypeOf((*MockSageMakerPaginators)(nil).NewListEndpointsPaginator), varargs...)
+}
+
+// NewListModelsPaginator mocks base method.
+func (m *MockSageMakerPaginators) NewListModelsPaginator(params *sagemaker.ListModelsInput, optFns ...func(*sagemaker.ListModelsPaginatorOptions)) SageMakerListModelsPaginator {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListModelsPaginator", varargs...)
+	ret0, _ := ret[0].(SageMakerListModelsPaginator)
+	return ret0
+}
+
+// NewListModelsPaginator indicates an expected call of NewListModelsPaginator.
+func (mr *MockSageMakerPaginatorsMockRecorder) NewListModelsPaginator(params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListModelsPaginator", reflect.TypeOf((*MockSageMakerPaginators)(nil).NewListModelsPaginator), varargs...)
+}
+
+// NewListProcessingJobsPaginator mocks base method.
+func (m *MockSageMakerPaginators) NewListProcessingJobsPaginator(params *sagemaker.ListProcessingJobsInput, optFns ...func(*sagemaker.ListProcessingJobsPaginatorOptions)) SageMakerListProcessingJobsPaginator {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListProcessingJobsPaginator", varargs...)
+	ret0, _ := ret[0].(SageMakerListProcessingJobsPaginator)
+	return ret0
+}
+
+// NewListProcessingJobsPaginator indicates an expected call of NewListProcessingJobsPaginator.
+func (mr *MockSageMakerPaginatorsMockRecorder) NewListProcessingJobsPaginator(params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListProcessingJobsPaginator", reflect.TypeOf((*MockSageMakerPaginators)(nil).NewListProcessingJobsPaginator), varargs...)
+}
+
+// NewListTrainingJobsPaginator mocks base method.
+func (m *MockSageMakerPaginators) NewListTrainingJobsPaginator(params *sagemaker.ListTrainingJobsInput, optFns ...func(*sagemaker.ListTrainingJobsPaginatorOptions)) SageMakerListTrainingJobsPaginator {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListTrainingJobsPaginator", varargs...)
+	ret0, _ := ret[0].(SageMakerListTrainingJobsPaginator)
+	return ret0
+}
+
+// NewListTrainingJobsPaginator indicates an expected call of NewListTrainingJobsPaginator.
+func (mr *MockSageMakerPaginatorsMockRecorder) NewListTrainingJobsPaginator(params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListTrainingJobsPaginator", reflect.TypeOf((*MockSageMakerPaginators)(nil).NewListTrainingJobsPaginator), varargs...)
+}
+
+// MockSageMakerListModelsPaginator is a mock of SageMakerListModelsPaginator interface.
+type MockSageMakerListModelsPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockSageMakerListModelsPaginatorMockRecorder
+}
+
+// MockSageMakerListModelsPaginatorMockRecorder is the mock recorder for MockSageMakerListModelsPaginator.
+type MockSageMakerListModelsPaginatorMockRecorder struct {
+	mock *MockSageMakerListModelsPaginator
+}
+
+// NewMockSageMakerListModelsPaginator creates a new mock instance.
+func NewMockSageMakerListModelsPaginator(ctrl *gomock.Controller) *MockSageMakerListModelsPaginator {
+	mock := &MockSageMakerListModelsPaginator{ctrl: ctrl}
+	mock.recorder = &MockSageMakerListModelsPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSageMakerListModelsPaginator) EXPECT() *MockSageMakerListModelsPaginatorMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockSageMakerListModelsPaginator) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockSageMakerListModelsPaginatorMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockSageMakerListModelsPaginator)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockSageMakerListModelsPaginator) NextPage(ctx context.Context, optFns ...func(*sagemaker.Options)) (*sagemaker.ListModelsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*sagemaker.ListModelsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockSageMakerListModelsPaginatorMockRecorder) NextPage(ctx interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockSageMakerListModelsPaginator)(nil).NextPage), varargs...)
+}
+
+// MockSageMakerListEndpointsPaginator is a mock of SageMakerListEndpointsPaginator interface.
+type MockSageMakerListEndpointsPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockSageMakerListEndpointsPaginatorMockRecorder
+}
+
+// MockSageMakerListEndpointsPaginatorMockRecorder is the mock recorder for MockSageMakerListEndpointsPaginator.
+type MockSageMakerListEndpointsPaginatorMockRecorder struct {
+	mock *MockSageMakerListEndpointsPaginator
+}
+
+// NewMockSageMakerListEndpointsPaginator creates a new mock instance.
+func NewMockSageMakerListEndpointsPaginator(ctrl *gomock.Controller) *MockSageMakerListEndpointsPaginator {
+	mock := &MockSageMakerListEndpointsPaginator{ctrl: ctrl}
+	mock.recorder = &MockSageMakerListEndpointsPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSageMakerListEndpointsPaginator) EXPECT() *MockSageMakerListEndpointsPaginatorMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockSageMakerListEndpointsPaginator) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockSageMakerListEndpointsPaginatorMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockSageMakerListEndpointsPaginator)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockSageMakerListEndpointsPaginator) NextPage(ctx context.Context, optFns ...func(*sagemaker.Options)) (*sagemaker.ListEndpointsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*sagemaker.ListEndpointsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockSageMakerListEndpointsPaginatorMockRecorder) NextPage(ctx interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockSageMakerListEndpointsPaginator)(nil).NextPage), varargs...)
+}
+
+// MockSageMakerListTrainingJobsPaginator is a mock of SageMakerListTrainingJobsPaginator interface.
+type MockSageMakerListTrainingJobsPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockSageMakerListTrainingJobsPaginatorMockRecorder
+}
+
+// MockSageMakerListTrainingJobsPaginatorMockRecorder is the mock recorder for MockSageMakerListTrainingJobsPaginator.
+type MockSageMakerListTrainingJobsPaginatorMockRecorder struct {
+	mock *MockSageMakerListTrainingJobsPaginator
+}
+
+// NewMockSageMakerListTrainingJobsPaginator creates a new mock instance.
+func NewMockSageMakerListTrainingJobsPaginator(ctrl *gomock.Controller) *MockSageMakerListTrainingJobsPaginator {
+	mock := &MockSageMakerListTrainingJobsPaginator{ctrl: ctrl}
+	mock.recorder = &MockSageMakerListTrainingJobsPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSageMakerListTrainingJobsPaginator) EXPECT() *MockSageMakerListTrainingJobsPaginatorMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockSageMakerListTrainingJobsPaginator) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockSageMakerListTrainingJobsPaginatorMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockSageMakerListTrainingJobsPaginator)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockSageMakerListTrainingJobsPaginator) NextPage(ctx context.Context, optFns ...func(*sagemaker.Options)) (*sagemaker.ListTrainingJobsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*sagemaker.ListTrainingJobsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockSageMakerListTrainingJobsPaginatorMockRecorder) NextPage(ctx interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockSageMakerListTrainingJobsPaginator)(nil).NextPage), varargs...)
+}
+
+// MockSageMakerListProcessingJobsPaginator is a mock of SageMakerListProcessingJobsPaginator interface.
+type MockSageMakerListProcessingJobsPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockSageMakerListProcessingJobsPaginatorMockRecorder
+}
+
+// MockSageMakerListProcessingJobsPaginatorMockRecorder is the mock recorder for MockSageMakerListProcessingJobsPaginator.
+type MockSageMakerListProcessingJobsPaginatorMockRecorder struct {
+	mock *MockSageMakerListProcessingJobsPaginator
+}
+
+// NewMockSageMakerListProcessingJobsPaginator creates a new mock instance.
+func NewMockSageMakerListProcessingJobsPaginator(ctrl *gomock.Controller) *MockSageMakerListProcessingJobsPaginator {
+	mock := &MockSageMakerListProcessingJobsPaginator{ctrl: ctrl}
+	mock.recorder = &MockSageMakerListProcessingJobsPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSageMakerListProcessingJobsPaginator) EXPECT() *MockSageMakerListProcessingJobsPaginatorMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockSageMakerListProcessingJobsPaginator) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockSageMakerListProcessingJobsPaginatorMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockSageMakerListProcessingJobsPaginator)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockSageMakerListProcessingJobsPaginator) NextPage(ctx context.Context, optFns ...func(*sagemaker.Options)) (*sagemaker.ListProcessingJobsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*sagemaker.ListProcessingJobsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockSageMakerListProcessingJobsPaginatorMockRecorder) NextPage(ctx interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockSageMakerListProcessingJobsPaginator)(nil).NextPage), varargs...)
+}
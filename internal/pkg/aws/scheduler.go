@@ -0,0 +1,32 @@
+package aws
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+)
+
+func newSchedulerClient(cfg aws.Config) *scheduler.Client {
+	return scheduler.NewFromConfig(cfg)
+}
+
+type SchedulerClient interface {
+	GetSchedule(ctx context.Context, params *scheduler.GetScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.GetScheduleOutput, error)
+}
+
+type SchedulerPaginators interface {
+	NewListSchedulesPaginator(params *scheduler.ListSchedulesInput, optFns ...func(*scheduler.ListSchedulesPaginatorOptions)) SchedulerListSchedulesPaginator
+}
+
+type schedulerPaginators struct {
+	client *scheduler.Client
+}
+
+func (s *schedulerPaginators) NewListSchedulesPaginator(params *scheduler.ListSchedulesInput, optFns ...func(*scheduler.ListSchedulesPaginatorOptions)) SchedulerListSchedulesPaginator {
+	return scheduler.NewListSchedulesPaginator(s.client, params, optFns...)
+}
+
+type SchedulerListSchedulesPaginator interface {
+	HasMorePages() bool
+	NextPage(ctx context.Context, optFns ...func(*scheduler.Options)) (*scheduler.ListSchedulesOutput, error)
+}
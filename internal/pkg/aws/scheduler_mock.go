@@ -0,0 +1,155 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/pkg/aws/scheduler.go
+
+// Package aws is a generated GoMock package.
+package aws
+
+import (
+	context "context"
+	reflect "reflect"
+
+	scheduler "github.com/aws/aws-sdk-go-v2/service/scheduler"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSchedulerClient is a mock of SchedulerClient interface.
+type MockSchedulerClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchedulerClientMockRecorder
+}
+
+// MockSchedulerClientMockRecorder is the mock recorder for MockSchedulerClient.
+type MockSchedulerClientMockRecorder struct {
+	mock *MockSchedulerClient
+}
+
+// NewMockSchedulerClient creates a new mock instance.
+func NewMockSchedulerClient(ctrl *gomock.Controller) *MockSchedulerClient {
+	mock := &MockSchedulerClient{ctrl: ctrl}
+	mock.recorder = &MockSchedulerClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSchedulerClient) EXPECT() *MockSchedulerClientMockRecorder {
+	return m.recorder
+}
+
+// GetSchedule mocks base method.
+func (m *MockSchedulerClient) GetSchedule(ctx context.Context, params *scheduler.GetScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.GetScheduleOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetSchedule", varargs...)
+	ret0, _ := ret[0].(*scheduler.GetScheduleOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchedule indicates an expected call of GetSchedule.
+func (mr *MockSchedulerClientMockRecorder) GetSchedule(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedule", reflect.TypeOf((*MockSchedulerClient)(nil).GetSchedule), varargs...)
+}
+
+// MockSchedulerPaginators is a mock of SchedulerPaginators interface.
+type MockSchedulerPaginators struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchedulerPaginatorsMockRecorder
+}
+
+// MockSchedulerPaginatorsMockRecorder is the mock recorder for MockSchedulerPaginators.
+type MockSchedulerPaginatorsMockRecorder struct {
+	mock *MockSchedulerPaginators
+}
+
+// NewMockSchedulerPaginators creates a new mock instance.
+func NewMockSchedulerPaginators(ctrl *gomock.Controller) *MockSchedulerPaginators {
+	mock := &MockSchedulerPaginators{ctrl: ctrl}
+	mock.recorder = &MockSchedulerPaginatorsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSchedulerPaginators) EXPECT() *MockSchedulerPaginatorsMockRecorder {
+	return m.recorder
+}
+
+// NewListSchedulesPaginator mocks base method.
+func (m *MockSchedulerPaginators) NewListSchedulesPaginator(params *scheduler.ListSchedulesInput, optFns ...func(*scheduler.ListSchedulesPaginatorOptions)) SchedulerListSchedulesPaginator {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListSchedulesPaginator", varargs...)
+	ret0, _ := ret[0].(SchedulerListSchedulesPaginator)
+	return ret0
+}
+
+// NewListSchedulesPaginator indicates an expected call of NewListSchedulesPaginator.
+func (mr *MockSchedulerPaginatorsMockRecorder) NewListSchedulesPaginator(params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListSchedulesPaginator", reflect.TypeOf((*MockSchedulerPaginators)(nil).NewListSchedulesPaginator), varargs...)
+}
+
+// MockSchedulerListSchedulesPaginator is a mock of SchedulerListSchedulesPaginator interface.
+type MockSchedulerListSchedulesPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchedulerListSchedulesPaginatorMockRecorder
+}
+
+// MockSchedulerListSchedulesPaginatorMockRecorder is the mock recorder for MockSchedulerListSchedulesPaginator.
+type MockSchedulerListSchedulesPaginatorMockRecorder struct {
+	mock *MockSchedulerListSchedulesPaginator
+}
+
+// NewMockSchedulerListSchedulesPaginator creates a new mock instance.
+func NewMockSchedulerListSchedulesPaginator(ctrl *gomock.Controller) *MockSchedulerListSchedulesPaginator {
+	mock := &MockSchedulerListSchedulesPaginator{ctrl: ctrl}
+	mock.recorder = &MockSchedulerListSchedulesPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSchedulerListSchedulesPaginator) EXPECT() *MockSchedulerListSchedulesPaginatorMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockSchedulerListSchedulesPaginator) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockSchedulerListSchedulesPaginatorMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockSchedulerListSchedulesPaginator)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockSchedulerListSchedulesPaginator) NextPage(ctx context.Context, optFns ...func(*scheduler.Options)) (*scheduler.ListSchedulesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*scheduler.ListSchedulesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockSchedulerListSchedulesPaginatorMockRecorder) NextPage(ctx interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockSchedulerListSchedulesPaginator)(nil).NextPage), varargs...)
+}
@@ -10,6 +10,10 @@ func newSsmClient(cfg aws.Config) *ssm.Client {
 	return ssm.NewFromConfig(cfg)
 }
 
+type SsmClient interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
 type SsmPaginators interface {
 	NewGetParametersByPathPaginator(params *ssm.GetParametersByPathInput, optFns ...func(*ssm.GetParametersByPathPaginatorOptions)) SsmGetParametersByPathPaginator
 }
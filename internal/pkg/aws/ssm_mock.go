@@ -12,6 +12,49 @@ import (
 	gomock "github.com/golang/mock/gomock"
 )
 
+// MockSsmClient is a mock of SsmClient interface.
+type MockSsmClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockSsmClientMockRecorder
+}
+
+// MockSsmClientMockRecorder is the mock recorder for MockSsmClient.
+type MockSsmClientMockRecorder struct {
+	mock *MockSsmClient
+}
+
+// NewMockSsmClient creates a new mock instance.
+func NewMockSsmClient(ctrl *gomock.Controller) *MockSsmClient {
+	mock := &MockSsmClient{ctrl: ctrl}
+	mock.recorder = &MockSsmClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSsmClient) EXPECT() *MockSsmClientMockRecorder {
+	return m.recorder
+}
+
+// GetParameter mocks base method.
+func (m *MockSsmClient) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetParameter", varargs...)
+	ret0, _ := ret[0].(*ssm.GetParameterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetParameter indicates an expected call of GetParameter.
+func (mr *MockSsmClientMockRecorder) GetParameter(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParameter", reflect.TypeOf((*MockSsmClient)(nil).GetParameter), varargs...)
+}
+
 // MockSsmPaginators is a mock of SsmPaginators interface.
 type MockSsmPaginators struct {
 	ctrl     *gomock.Controller
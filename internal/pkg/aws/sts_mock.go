@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/pkg/aws/sts.go
+
+// Package aws is a generated GoMock package.
+package aws
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	sts "github.com/aws/aws-sdk-go-v2/service/sts"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStsPresignClient is a mock of StsPresignClient interface.
+type MockStsPresignClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockStsPresignClientMockRecorder
+}
+
+// MockStsPresignClientMockRecorder is the mock recorder for MockStsPresignClient.
+type MockStsPresignClientMockRecorder struct {
+	mock *MockStsPresignClient
+}
+
+// NewMockStsPresignClient creates a new mock instance.
+func NewMockStsPresignClient(ctrl *gomock.Controller) *MockStsPresignClient {
+	mock := &MockStsPresignClient{ctrl: ctrl}
+	mock.recorder = &MockStsPresignClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStsPresignClient) EXPECT() *MockStsPresignClientMockRecorder {
+	return m.recorder
+}
+
+// PresignGetCallerIdentity mocks base method.
+func (m *MockStsPresignClient) PresignGetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.PresignOptions)) (*v4.PresignedHTTPRequest, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PresignGetCallerIdentity", varargs...)
+	ret0, _ := ret[0].(*v4.PresignedHTTPRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PresignGetCallerIdentity indicates an expected call of PresignGetCallerIdentity.
+func (mr *MockStsPresignClientMockRecorder) PresignGetCallerIdentity(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PresignGetCallerIdentity", reflect.TypeOf((*MockStsPresignClient)(nil).PresignGetCallerIdentity), varargs...)
+}
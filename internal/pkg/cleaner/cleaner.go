@@ -8,154 +8,495 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
 	"github.com/aws/smithy-go/ptr"
 	boxaws "github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/aws"
+	boxkubernetes "github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/kubernetes"
+	"github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/reference"
+	"github.com/hashicorp/go-hclog"
 	gerrors "github.com/pkg/errors"
 	"strconv"
 	"time"
 )
 
 type Config struct {
-	DryRun          bool
-	DefaultKeepDays int
+	DryRun           bool
+	DefaultKeepDays  int
+	DefaultKeepCount int
+	DefaultAgeBasis  AgeBasis
+	// DefaultUntaggedKeepDays is the minimal age of removed untagged images, nil means that untagged images are retained
+	// the same way as tagged ones
+	DefaultUntaggedKeepDays *int
+	// EcrLifecyclePolicies enables evaluation of lifecycle policies of repositories instead of the other rules
+	EcrLifecyclePolicies bool
+	// DefaultLifecyclePolicy is evaluated instead of the other rules for repositories without a lifecycle policy
+	DefaultLifecyclePolicy *LifecyclePolicy
+	// Policy takes precedence over repository tags, which take precedence over the defaults
+	Policy Policy
+	// RepositoryConcurrency is the number of repositories processed at the same time, values lower than 1 mean 1
+	RepositoryConcurrency int
+
+	ScheduledTasksEnabled        bool
+	LambdaReferencedVersionsOnly bool
+	BatchEnabled                 bool
+	SageMakerEnabled             bool
+	CodeBuildEnabled             bool
 }
 
-func New(awsProvider *boxaws.Provider, config Config) *Cleaner {
+// New creates a cleaner of repositories available through awsProvider. Images used by workloads are discovered using
+// awsProvider and, additionally, usageAwsProviders (e.g. other regions pulling images from this registry).
+func New(
+	awsProvider *boxaws.Provider,
+	usageAwsProviders []*boxaws.Provider,
+	kubernetesClusters []boxkubernetes.Cluster,
+	config Config,
+) *Cleaner {
 	return &Cleaner{
-		awsProvider: awsProvider,
-		config:      config,
+		awsProvider:        awsProvider,
+		usageAwsProviders:  usageAwsProviders,
+		kubernetesClusters: kubernetesClusters,
+		config:             config,
 	}
 }
 
 type Cleaner struct {
-	awsProvider *boxaws.Provider
-	config      Config
+	awsProvider        *boxaws.Provider
+	usageAwsProviders  []*boxaws.Provider
+	kubernetesClusters []boxkubernetes.Cluster
+	config             Config
 }
 
 const (
-	BoxCleanerEnabledTag  = "BoxCleanerEnabled"
-	BoxCleanerKeepDaysTag = "BoxCleanerKeepDays"
+	BoxCleanerEnabledTag            = "BoxCleanerEnabled"
+	BoxCleanerKeepDaysTag           = "BoxCleanerKeepDays"
+	BoxCleanerKeepCountTag          = "BoxCleanerKeepCount"
+	BoxCleanerTagRulesTag           = "BoxCleanerTagRules"
+	BoxCleanerAgeBasisTag           = "BoxCleanerAgeBasis"
+	BoxCleanerUntaggedKeepDaysTag   = "BoxCleanerUntaggedKeepDays"
+	BoxCleanerEcrLifecyclePolicyTag = "BoxCleanerEcrLifecyclePolicy"
 )
 
-func (c *Cleaner) Clean(startTime time.Time) error {
-	usedImagesSet, err := (&usedImages{awsProvider: c.awsProvider}).getImages()
+// Result summarizes removal of unused images, all counts are numbers of image ids (tags or digests of untagged images).
+type Result struct {
+	// UnusedImageIds were found unused (and removed unless in dry run mode)
+	UnusedImageIds  int `json:"unusedImageIds"`
+	RemovedImageIds int `json:"removedImageIds"`
+	// NotFoundImageIds were removed by someone else in the meantime
+	NotFoundImageIds int `json:"notFoundImageIds"`
+	// KeptImageIds could not be removed because they are referenced by an image index, they are kept together with their
+	// dependents
+	KeptImageIds int `json:"keptImageIds"`
+	// FailedImageIds could not be removed because of other failures (e.g. KMS errors), the run fails if there are any
+	FailedImageIds int `json:"failedImageIds"`
+	// SkippedRepositories (a number of repositories, not image ids) were not cleaned because of invalid settings (e.g. a
+	// missing tag rule set), the run fails if there are any
+	SkippedRepositories int `json:"skippedRepositories"`
+}
+
+func (r *Result) add(other Result) {
+	r.UnusedImageIds += other.UnusedImageIds
+	r.RemovedImageIds += other.RemovedImageIds
+	r.NotFoundImageIds += other.NotFoundImageIds
+	r.KeptImageIds += other.KeptImageIds
+	r.FailedImageIds += other.FailedImageIds
+	r.SkippedRepositories += other.SkippedRepositories
+}
+
+// Clean removes unused images from all repositories. Image ids that cannot be removed and repositories with invalid
+// settings do not stop the cleaning of other images, they are reported in the result and, unless they are kept on
+// purpose, returned as an error at the end.
+func (c *Cleaner) Clean(ctx context.Context, startTime time.Time) (Result, error) {
+	usedImagesSet, err := (&usedImages{
+		awsProvider:        c.awsProvider,
+		usageAwsProviders:  c.usageAwsProviders,
+		kubernetesClusters: c.kubernetesClusters,
+		config:             c.config,
+		startTime:          startTime,
+	}).getImages(ctx)
 	if err != nil {
-		return gerrors.Wrapf(err, "error getting used images")
+		return Result{}, gerrors.Wrapf(err, "error getting used images")
 	}
 
 	logger.Info("Found used images", "len(usedImagesSet)", len(usedImagesSet))
 
+	repositories, err := c.getRepositories(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result, err := processRepositories(ctx, repositories, c.config.RepositoryConcurrency, logOutput,
+		func(ctx context.Context, logger hclog.Logger, repository types.Repository) (Result, error) {
+			return c.processSingleRepository(ctx, logger, repository, usedImagesSet, startTime)
+		})
+	if err != nil {
+		return result, err
+	}
+
+	logger.Info("Finished cleaning", "unusedImageIds", result.UnusedImageIds, "removedImageIds", result.RemovedImageIds,
+		"notFoundImageIds", result.NotFoundImageIds, "keptImageIds", result.KeptImageIds, "failedImageIds", result.FailedImageIds,
+		"skippedRepositories", result.SkippedRepositories)
+
+	if result.FailedImageIds > 0 && result.SkippedRepositories > 0 {
+		return result, gerrors.Errorf("cannot remove %v image ids and cannot clean %v repositories with invalid settings",
+			result.FailedImageIds, result.SkippedRepositories)
+	}
+	if result.FailedImageIds > 0 {
+		return result, gerrors.Errorf("cannot remove %v image ids", result.FailedImageIds)
+	}
+	if result.SkippedRepositories > 0 {
+		return result, gerrors.Errorf("cannot clean %v repositories with invalid settings", result.SkippedRepositories)
+	}
+	return result, nil
+}
+
+func (c *Cleaner) getRepositories(ctx context.Context) ([]types.Repository, error) {
 	ecrPaginators := c.awsProvider.EcrPaginators
 
+	var repositories []types.Repository
 	describeRepositoriesPaginator := ecrPaginators.NewDescribeRepositoriesPaginator(&ecr.DescribeRepositoriesInput{})
 	for describeRepositoriesPaginator.HasMorePages() {
-		describeRepositoriesPage, err := describeRepositoriesPaginator.NextPage(context.TODO())
+		describeRepositoriesPage, err := describeRepositoriesPaginator.NextPage(ctx)
 		if err != nil {
-			return gerrors.Wrapf(err, "cannot get describe repositories page")
-		}
-
-		for _, repository := range describeRepositoriesPage.Repositories {
-
-			err := c.processSingleRepository(repository, usedImagesSet, startTime)
-			if err != nil {
-				return gerrors.Wrapf(err, "error processig %v repository", *repository.RepositoryName)
-			}
+			return nil, gerrors.Wrapf(err, "cannot get describe repositories page")
 		}
+		repositories = append(repositories, describeRepositoriesPage.Repositories...)
 	}
-
-	return nil
+	return repositories, nil
 }
 
-func (c *Cleaner) processSingleRepository(repository types.Repository, usedImagesSet map[string]struct{}, startTime time.Time) error {
+func (c *Cleaner) processSingleRepository(ctx context.Context, logger hclog.Logger, repository types.Repository, usedImagesSet map[string]struct{}, startTime time.Time) (Result, error) {
 	ecrClient := c.awsProvider.EcrClient
 
-	listTagsForResourceOutput, err := ecrClient.ListTagsForResource(context.TODO(), &ecr.ListTagsForResourceInput{
+	listTagsForResourceOutput, err := ecrClient.ListTagsForResource(ctx, &ecr.ListTagsForResourceInput{
 		ResourceArn: repository.RepositoryArn,
 	})
 	if err != nil {
-		return gerrors.Wrapf(err, "cannot list tags for repository %v", *repository.RepositoryArn)
+		return Result{}, gerrors.Wrapf(err, "cannot list tags for repository %v", *repository.RepositoryArn)
 	}
 	repositoryTagsMap := convertTagsToMap(listTagsForResourceOutput.Tags)
 
 	logger.Debug("Found repository tags", "repository", *repository.RepositoryArn, "repositoryTagsMap", repositoryTagsMap)
 
-	if boxCleanerEnabledTagValue, ok := repositoryTagsMap[BoxCleanerEnabledTag]; ok && boxCleanerEnabledTagValue == "true" {
+	repositoryPolicy := c.config.Policy.getRepositoryPolicy(*repository.RepositoryName, repositoryTagsMap)
+	if repositoryPolicy != nil {
+		logger.Debug("Found repository policy", "repository", *repository.RepositoryArn, "selector", repositoryPolicy.Selector)
+	}
+
+	if isCleanerEnabled(repositoryPolicy, repositoryTagsMap) {
+
+		tagRules, err := c.getTagRules(repositoryPolicy, repositoryTagsMap)
+		if err != nil {
+			// an invalid repository tag only affects its own repository, the other repositories are still cleaned
+			logger.Error("Cannot get tag rules of repository, skipping", "repository", *repository.RepositoryArn,
+				"error", err)
+			return Result{SkippedRepositories: 1}, nil
+		}
+
+		lifecyclePolicy, err := c.getLifecyclePolicy(ctx, repository, repositoryPolicy, repositoryTagsMap)
+		if err != nil {
+			return Result{}, gerrors.Wrapf(err, "error getting lifecycle policy of %v repository", *repository.RepositoryName)
+		}
+
+		retention := retention{
+			keepDays:  c.countKeepDays(repositoryPolicy, repositoryTagsMap),
+			keepCount: c.countKeepCount(repositoryPolicy, repositoryTagsMap),
+			ageBasis:  c.getAgeBasis(repositoryPolicy, repositoryTagsMap),
+			tagRules:  tagRules,
+
+			untaggedKeepDays: c.countUntaggedKeepDays(repositoryPolicy, repositoryTagsMap),
+			lifecyclePolicy:  lifecyclePolicy,
+		}
+
+		result, err := c.cleanSingleRepository(ctx, logger, repository, usedImagesSet, retention, startTime)
+		if err != nil {
+			return result, gerrors.Wrapf(err, "error cleaning %v repository", *repository.RepositoryName)
+		}
+		return result, nil
+	}
+	return Result{}, nil
+}
+
+// cleanSingleRepository collects all images of the repository first, then decides which of them should be kept (taking
+// image indexes and referrers into account) and finally removes the rest.
+func (c *Cleaner) cleanSingleRepository(
+	ctx context.Context,
+	logger hclog.Logger,
+	repository types.Repository,
+	usedImagesSet map[string]struct{},
+	retention retention,
+	startTime time.Time,
+) (Result, error) {
+
+	images, err := c.getRepositoryImages(ctx, repository)
+	if err != nil {
+		return Result{}, err
+	}
+
+	imageIndexChildren, err := c.getImageIndexChildren(ctx, repository, images)
+	if err != nil {
+		return Result{}, gerrors.Wrapf(err, "error getting image indexes in repository %v", *repository.RepositoryName)
+	}
+
+	imageReferrers, err := c.getImageReferrers(ctx, logger, repository, images)
+	if err != nil {
+		return Result{}, gerrors.Wrapf(err, "error getting image referrers in repository %v", *repository.RepositoryName)
+	}
+
+	imageDependents := make(map[string][]string, len(imageIndexChildren)+len(imageReferrers))
+	for digest, children := range imageIndexChildren {
+		imageDependents[digest] = append(imageDependents[digest], children...)
+	}
+	for digest, referrers := range imageReferrers {
+		imageDependents[digest] = append(imageDependents[digest], referrers...)
+	}
 
-		keepDays := c.countKeepDays(repositoryTagsMap)
+	unusedImages := getUnusedImages(logger, repository, images, imageDependents, usedImagesSet, retention, startTime)
+
+	var result Result
+	// images that ECR refused to remove, their dependents (removed in later phases) are kept too
+	keptDigests := make(map[string]struct{})
+	for _, phaseImages := range getRemovalPhases(unusedImages, imageDependents) {
+		var removedImages []types.ImageDetail
+		for _, image := range phaseImages {
+			if _, ok := keptDigests[*image.ImageDigest]; ok {
+				logger.Info("Found dependent of an image that cannot be removed, keeping",
+					"repository", *repository.RepositoryUri, "imageDigest", *image.ImageDigest)
+				continue
+			}
+			removedImages = append(removedImages, image)
+		}
 
-		err := c.cleanSingleRepository(repository, usedImagesSet, keepDays, startTime)
+		phaseKeptDigests, err := c.processUnusedImages(ctx, logger, repository, removedImages, &result)
 		if err != nil {
-			return gerrors.Wrapf(err, "error cleaning %v repository", *repository.RepositoryName)
+			return result, gerrors.Wrapf(err, "error processig unused images in repository %v", *repository.RepositoryName)
+		}
+		for keptDigest := range phaseKeptDigests {
+			keepWithDependents(keptDigest, imageDependents, keptDigests)
 		}
 	}
-	return nil
+	return result, nil
 }
 
-func (c *Cleaner) cleanSingleRepository(repository types.Repository, usedImagesSet map[string]struct{}, keepDays int, startTime time.Time) error {
+// keepWithDependents adds the digest and digests of all its dependents (including dependents of dependents) to
+// keptDigests.
+func keepWithDependents(digest string, imageDependents map[string][]string, keptDigests map[string]struct{}) {
+	if _, ok := keptDigests[digest]; ok {
+		return
+	}
+	keptDigests[digest] = struct{}{}
+	for _, dependentDigest := range imageDependents[digest] {
+		keepWithDependents(dependentDigest, imageDependents, keptDigests)
+	}
+}
+
+func (c *Cleaner) getRepositoryImages(ctx context.Context, repository types.Repository) ([]types.ImageDetail, error) {
 	ecrPaginators := c.awsProvider.EcrPaginators
 
+	var images []types.ImageDetail
 	describeImagesPaginator := ecrPaginators.NewDescribeImagesPaginator(&ecr.DescribeImagesInput{
 		RepositoryName: repository.RepositoryName,
 	})
-
 	for describeImagesPaginator.HasMorePages() {
-		describeImagesPage, err := describeImagesPaginator.NextPage(context.TODO())
+		describeImagesPage, err := describeImagesPaginator.NextPage(ctx)
 		if err != nil {
-			return gerrors.Wrapf(err, "cannot get describe images page")
-		}
-		for _, image := range describeImagesPage.ImageDetails {
-			err := c.processSingleImage(repository, image, usedImagesSet, keepDays, startTime)
-			if err != nil {
-				return gerrors.Wrapf(err, "error processig %v image in repository %v", *image.ImageDigest, *repository.RepositoryName)
-			}
+			return nil, gerrors.Wrapf(err, "cannot get describe images page")
 		}
+		images = append(images, describeImagesPage.ImageDetails...)
 	}
-	return nil
+	return images, nil
 }
 
-func (c *Cleaner) processSingleImage(
+// getUnusedImages returns images that should be removed, images always go before their dependents. Dependents are
+// child manifests of image indexes and referrers (signatures, attestations, SBOMs) of their subject images. A dependent
+// is kept if any image it depends on is kept (or if it is in use itself), otherwise it is removed together with these
+// images, regardless of its age.
+func getUnusedImages(
+	logger hclog.Logger,
 	repository types.Repository,
-	image types.ImageDetail,
+	images []types.ImageDetail,
+	imageDependents map[string][]string,
 	usedImagesSet map[string]struct{},
-	keepDays int,
+	retention retention,
 	startTime time.Time,
-) error {
+) []types.ImageDetail {
+
+	dependentDigests := make(map[string]struct{})
+	for _, dependents := range imageDependents {
+		for _, dependentDigest := range dependents {
+			dependentDigests[dependentDigest] = struct{}{}
+		}
+	}
 
-	imageAgeDays := startTime.Sub(*image.ImagePushedAt).Hours() / 24
+	keptDigests := getRetainedImageDigests(logger, repository, images, dependentDigests, retention, startTime)
+	for _, image := range images {
+		if _, ok := keptDigests[*image.ImageDigest]; ok {
+			continue
+		}
+		if isImageUsed(logger, repository, image, usedImagesSet) {
+			keptDigests[*image.ImageDigest] = struct{}{}
+		}
+	}
 
-	if imageAgeDays > float64(keepDays) {
+	// dependents of kept images (including dependents of dependents, e.g. a signature of a child manifest) are kept too
+	keptDigestsToVisit := make([]string, 0, len(keptDigests))
+	for keptDigest := range keptDigests {
+		keptDigestsToVisit = append(keptDigestsToVisit, keptDigest)
+	}
+	for len(keptDigestsToVisit) > 0 {
+		dependencyDigest := keptDigestsToVisit[0]
+		keptDigestsToVisit = keptDigestsToVisit[1:]
 
-		imageDigest := *image.ImageDigest
+		for _, dependentDigest := range imageDependents[dependencyDigest] {
+			if _, ok := keptDigests[dependentDigest]; !ok {
+				logger.Debug("Found dependent of a kept image",
+					"repository", *repository.RepositoryUri, "keptImageDigest", dependencyDigest, "imageDigest", dependentDigest)
 
-		logger.Debug("Found old image", "repository", *repository.RepositoryUri, "imageAgeDays", imageAgeDays)
+				keptDigests[dependentDigest] = struct{}{}
+				keptDigestsToVisit = append(keptDigestsToVisit, dependentDigest)
+			}
+		}
+	}
 
-		for _, imageTag := range image.ImageTags {
-			// capture range variables
-			imageTag := imageTag
+	imagesByDigest := make(map[string]types.ImageDetail, len(images))
+	for _, image := range images {
+		imagesByDigest[*image.ImageDigest] = image
+	}
 
-			err := c.processSingleImageReference(imageReference{
-				repositoryUri:  *repository.RepositoryUri,
-				repositoryName: *repository.RepositoryName,
-				digest:         imageDigest,
-				tag:            &imageTag,
-			}, usedImagesSet)
-			if err != nil {
-				return gerrors.Wrapf(err, "error processig %v image tag in repository %v", imageTag, *repository.RepositoryName)
+	var unusedImages []types.ImageDetail
+	addedDigests := make(map[string]struct{})
+	var addUnusedImage func(image types.ImageDetail)
+	addUnusedImage = func(image types.ImageDetail) {
+		if _, ok := keptDigests[*image.ImageDigest]; ok {
+			return
+		}
+		if _, ok := addedDigests[*image.ImageDigest]; ok {
+			return
+		}
+		addedDigests[*image.ImageDigest] = struct{}{}
+		unusedImages = append(unusedImages, image)
+
+		for _, dependentDigest := range imageDependents[*image.ImageDigest] {
+			if dependent, ok := imagesByDigest[dependentDigest]; ok {
+				addUnusedImage(dependent)
 			}
 		}
+	}
+	for _, image := range images {
+		if _, isDependent := dependentDigests[*image.ImageDigest]; !isDependent {
+			addUnusedImage(image)
+		}
+	}
+
+	return unusedImages
+}
+
+// isImageUsed checks if the image is in use.
+func isImageUsed(logger hclog.Logger, repository types.Repository, image types.ImageDetail, usedImagesSet map[string]struct{}) bool {
+	// all tags point to the same manifest, so if any of them (or the digest) is in use, the whole image is kept
+	for _, reference := range getImageReferences(repository, image) {
+		if isImageInUse(logger, reference, usedImagesSet) {
+			return true
+		}
+	}
 
+	return false
+}
+
+func getImageReferences(repository types.Repository, image types.ImageDetail) []imageReference {
+	references := make([]imageReference, 0, len(image.ImageTags))
+	for _, imageTag := range image.ImageTags {
+		// capture range variables
+		imageTag := imageTag
+
+		references = append(references, imageReference{
+			repositoryUri:  *repository.RepositoryUri,
+			repositoryName: *repository.RepositoryName,
+			digest:         *image.ImageDigest,
+			tag:            &imageTag,
+		})
+	}
+
+	if len(image.ImageTags) == 0 {
+		references = append(references, imageReference{
+			repositoryUri:  *repository.RepositoryUri,
+			repositoryName: *repository.RepositoryName,
+			digest:         *image.ImageDigest,
+		})
+	}
+
+	return references
+}
+
+// getRemovalPhases splits unused images into phases removed one after another. An image is removed in a later phase than
+// all unused images it depends on, so image indexes are removed before their child manifests and subject images before
+// their referrers (ECR refuses to remove a child manifest of an existing image index).
+func getRemovalPhases(unusedImages []types.ImageDetail, imageDependents map[string][]string) [][]types.ImageDetail {
+	unusedDigests := make(map[string]struct{}, len(unusedImages))
+	for _, image := range unusedImages {
+		unusedDigests[*image.ImageDigest] = struct{}{}
+	}
+
+	unusedDependencies := make(map[string][]string)
+	for dependencyDigest, dependents := range imageDependents {
+		if _, ok := unusedDigests[dependencyDigest]; !ok {
+			continue
+		}
+		for _, dependentDigest := range dependents {
+			unusedDependencies[dependentDigest] = append(unusedDependencies[dependentDigest], dependencyDigest)
+		}
+	}
+
+	phases := make(map[string]int, len(unusedImages))
+	var getPhase func(digest string) int
+	getPhase = func(digest string) int {
+		if phase, ok := phases[digest]; ok {
+			return phase
+		}
+		phase := 0
+		for _, dependencyDigest := range unusedDependencies[digest] {
+			if dependencyPhase := getPhase(dependencyDigest) + 1; dependencyPhase > phase {
+				phase = dependencyPhase
+			}
+		}
+		phases[digest] = phase
+		return phase
+	}
+
+	var removalPhases [][]types.ImageDetail
+	for _, image := range unusedImages {
+		phase := getPhase(*image.ImageDigest)
+		for len(removalPhases) <= phase {
+			removalPhases = append(removalPhases, nil)
+		}
+		removalPhases[phase] = append(removalPhases[phase], image)
+	}
+	return removalPhases
+}
+
+// processUnusedImages removes all references of the images (or only logs them in dry run mode) and returns digests of
+// images that cannot be removed because they are referenced by image indexes.
+func (c *Cleaner) processUnusedImages(ctx context.Context, logger hclog.Logger, repository types.Repository, images []types.ImageDetail, result *Result) (map[string]struct{}, error) {
+	var references []imageReference
+	for _, image := range images {
 		if len(image.ImageTags) == 0 {
-			logger.Debug("Found untagged image", "imageDigest", imageDigest)
-
-			err := c.processSingleImageReference(imageReference{
-				repositoryUri:  *repository.RepositoryUri,
-				repositoryName: *repository.RepositoryName,
-				digest:         imageDigest,
-			}, usedImagesSet)
-			if err != nil {
-				return gerrors.Wrapf(err, "error processig %v image tag in repository %v", imageDigest, *repository.RepositoryName)
+			logger.Debug("Found untagged image", "imageDigest", *image.ImageDigest)
+		}
+
+		for _, reference := range getImageReferences(repository, image) {
+			result.UnusedImageIds++
+			if c.config.DryRun {
+				logger.Info("Found unused image, should be removed",
+					"imageReference", reference)
+			} else {
+				logger.Info("Found unused image, removing",
+					"imageReference", reference)
+
+				references = append(references, reference)
 			}
 		}
 	}
-	return nil
+
+	if len(references) == 0 {
+		return nil, nil
+	}
+	return c.deleteImages(ctx, logger, repository, references, result)
 }
 
 type imageReference struct {
@@ -185,71 +526,133 @@ func (i imageReference) digestId() string {
 	return fmt.Sprintf("%v@%v", i.repositoryUri, i.digest)
 }
 
-func (c *Cleaner) processSingleImageReference(reference imageReference, usedImagesSet map[string]struct{}) error {
-
-	if !isImageInUse(reference, usedImagesSet) {
-		if c.config.DryRun {
-			logger.Info("Found unused image, should be removed",
-				"imageReference", reference)
-		} else {
-			logger.Info("Found unused image, removing",
-				"imageReference", reference)
+func isImageInUse(logger hclog.Logger, imageRef imageReference, usedImagesSet map[string]struct{}) bool {
+	var imageIds []string
+	if imageTagId := imageRef.tagId(); imageTagId != nil {
+		imageIds = append(imageIds, reference.Normalize(*imageTagId)...)
+	}
+	imageIds = append(imageIds, reference.Normalize(imageRef.digestId())...)
 
-			err := c.deleteImage(reference)
-			if err != nil {
-				return gerrors.Wrapf(err, "error deleting image %v", reference)
-			}
+	for _, imageId := range imageIds {
+		if _, ok := usedImagesSet[imageId]; ok {
+			logger.Info("Found old image in use", "imageId", imageId)
+			return true
 		}
 	}
-	return nil
-}
 
-func isImageInUse(reference imageReference, usedImagesSet map[string]struct{}) bool {
-	imageTagId := reference.tagId()
-	imageDigestId := reference.digestId()
+	return false
+}
 
-	if imageTagId != nil {
-		if _, ok := usedImagesSet[*imageTagId]; ok {
-			logger.Info("Found old image in use", "imageId", *imageTagId)
-			return true
+func (i imageReference) imageIdentifier() types.ImageIdentifier {
+	if i.tag != nil {
+		return types.ImageIdentifier{
+			ImageTag: i.tag,
+		}
+	} else {
+		return types.ImageIdentifier{
+			ImageDigest: aws.String(i.digest),
 		}
 	}
+}
+
+// maxBatchDeleteImageIds is the maximum number of image ids accepted by a single BatchDeleteImage call.
+const maxBatchDeleteImageIds = 100
+
+// deleteImages removes the image references from the repository in batches of up to maxBatchDeleteImageIds, adds the
+// outcome to the result and returns digests of images that are still in the repository (referenced by image indexes or
+// failed to be removed), so their dependents are kept. Failures of single image ids are logged and counted, only errors
+// of whole calls are returned.
+func (c *Cleaner) deleteImages(ctx context.Context, logger hclog.Logger, repository types.Repository, references []imageReference, result *Result) (map[string]struct{}, error) {
+	ecrClient := c.awsProvider.EcrClient
 
-	if _, ok := usedImagesSet[imageDigestId]; ok {
-		logger.Info("Found old image in use", "imageId", imageDigestId)
-		return true
+	// failures identify image ids by tags or by digests
+	digestsByTag := make(map[string]string, len(references))
+	for _, reference := range references {
+		if reference.tag != nil {
+			digestsByTag[*reference.tag] = reference.digest
+		}
 	}
 
-	return false
-}
+	keptDigests := make(map[string]struct{})
+	for batchStart := 0; batchStart < len(references); batchStart += maxBatchDeleteImageIds {
+		batchEnd := batchStart + maxBatchDeleteImageIds
+		if batchEnd > len(references) {
+			batchEnd = len(references)
+		}
 
-func (c *Cleaner) deleteImage(reference imageReference) error {
-	ecrClient := c.awsProvider.EcrClient
+		imageIds := make([]types.ImageIdentifier, 0, batchEnd-batchStart)
+		for _, reference := range references[batchStart:batchEnd] {
+			imageIds = append(imageIds, reference.imageIdentifier())
+		}
 
-	var imageIdentifier types.ImageIdentifier
-	if reference.tag != nil {
-		imageIdentifier = types.ImageIdentifier{
-			ImageTag: reference.tag,
+		batchDeleteImageOutput, err := ecrClient.BatchDeleteImage(ctx, &ecr.BatchDeleteImageInput{
+			ImageIds:       imageIds,
+			RepositoryName: repository.RepositoryName,
+		})
+		if err != nil {
+			return keptDigests, gerrors.Wrapf(err, "cannot remove %v images from repository", len(imageIds))
 		}
-	} else {
-		imageIdentifier = types.ImageIdentifier{
-			ImageDigest: aws.String(reference.digest),
+
+		result.RemovedImageIds += len(imageIds) - len(batchDeleteImageOutput.Failures)
+		for _, failure := range batchDeleteImageOutput.Failures {
+			var imageId types.ImageIdentifier
+			if failure.ImageId != nil {
+				imageId = *failure.ImageId
+			}
+			digest := aws.ToString(imageId.ImageDigest)
+			if digest == "" {
+				digest = digestsByTag[aws.ToString(imageId.ImageTag)]
+			}
+
+			switch failure.FailureCode {
+			case types.ImageFailureCodeImageReferencedByManifestList:
+				logger.Info("Found unused image referenced by an image index, keeping", "repository", *repository.RepositoryUri,
+					"imageDigest", digest, "imageTag", aws.ToString(imageId.ImageTag),
+					"failureCode", failure.FailureCode, "failureReason", aws.ToString(failure.FailureReason))
+
+				result.KeptImageIds++
+				if digest != "" {
+					keptDigests[digest] = struct{}{}
+				}
+			case types.ImageFailureCodeImageNotFound:
+				logger.Warn("Found unused image removed in the meantime", "repository", *repository.RepositoryUri,
+					"imageDigest", digest, "imageTag", aws.ToString(imageId.ImageTag),
+					"failureCode", failure.FailureCode, "failureReason", aws.ToString(failure.FailureReason))
+
+				result.NotFoundImageIds++
+			default:
+				logger.Error("Cannot remove unused image", "repository", *repository.RepositoryUri,
+					"imageDigest", digest, "imageTag", aws.ToString(imageId.ImageTag),
+					"failureCode", failure.FailureCode, "failureReason", aws.ToString(failure.FailureReason))
+
+				result.FailedImageIds++
+				// the image (or the manifest of a tag) is still there, its signatures and other referrers must stay too
+				if digest != "" {
+					keptDigests[digest] = struct{}{}
+				}
+			}
 		}
 	}
+	return keptDigests, nil
+}
 
-	_, err := ecrClient.BatchDeleteImage(context.TODO(), &ecr.BatchDeleteImageInput{
-		ImageIds: []types.ImageIdentifier{
-			imageIdentifier,
-		},
-		RepositoryName: aws.String(reference.repositoryName),
-	})
-	if err != nil {
-		return gerrors.Wrapf(err, "cannot remove image %v from repository", reference)
+// isCleanerEnabled checks if the repository should be cleaned, the BoxCleanerEnabled=false tag always disables cleaning,
+// so repository owners can opt out of the policy.
+func isCleanerEnabled(repositoryPolicy *RepositoryPolicy, repositoryTagsMap map[string]string) bool {
+	boxCleanerEnabledTagValue, ok := repositoryTagsMap[BoxCleanerEnabledTag]
+	if ok && boxCleanerEnabledTagValue == "false" {
+		return false
+	}
+	if repositoryPolicy != nil && repositoryPolicy.Enabled != nil {
+		return *repositoryPolicy.Enabled
 	}
-	return nil
+	return ok && boxCleanerEnabledTagValue == "true"
 }
 
-func (c *Cleaner) countKeepDays(repositoryTagsMap map[string]string) int {
+func (c *Cleaner) countKeepDays(repositoryPolicy *RepositoryPolicy, repositoryTagsMap map[string]string) int {
+	if repositoryPolicy != nil && repositoryPolicy.KeepDays != nil {
+		return *repositoryPolicy.KeepDays
+	}
 	keepDays := c.config.DefaultKeepDays
 	if boxCleanerKeepDaysTagValueStr, ok := repositoryTagsMap[BoxCleanerKeepDaysTag]; ok {
 		boxCleanerKeepDaysTagValue, err := strconv.Atoi(boxCleanerKeepDaysTagValueStr)
@@ -260,6 +663,132 @@ func (c *Cleaner) countKeepDays(repositoryTagsMap map[string]string) int {
 	return keepDays
 }
 
+func (c *Cleaner) countKeepCount(repositoryPolicy *RepositoryPolicy, repositoryTagsMap map[string]string) int {
+	if repositoryPolicy != nil && repositoryPolicy.KeepCount != nil {
+		return *repositoryPolicy.KeepCount
+	}
+	keepCount := c.config.DefaultKeepCount
+	if boxCleanerKeepCountTagValueStr, ok := repositoryTagsMap[BoxCleanerKeepCountTag]; ok {
+		boxCleanerKeepCountTagValue, err := strconv.Atoi(boxCleanerKeepCountTagValueStr)
+		if err == nil {
+			keepCount = boxCleanerKeepCountTagValue
+		}
+	}
+	return keepCount
+}
+
+func (c *Cleaner) countUntaggedKeepDays(repositoryPolicy *RepositoryPolicy, repositoryTagsMap map[string]string) *int {
+	if repositoryPolicy != nil && repositoryPolicy.UntaggedKeepDays != nil {
+		return repositoryPolicy.UntaggedKeepDays
+	}
+	untaggedKeepDays := c.config.DefaultUntaggedKeepDays
+	if boxCleanerUntaggedKeepDaysTagValueStr, ok := repositoryTagsMap[BoxCleanerUntaggedKeepDaysTag]; ok {
+		boxCleanerUntaggedKeepDaysTagValue, err := strconv.Atoi(boxCleanerUntaggedKeepDaysTagValueStr)
+		if err == nil && boxCleanerUntaggedKeepDaysTagValue >= 0 {
+			untaggedKeepDays = &boxCleanerUntaggedKeepDaysTagValue
+		}
+	}
+	return untaggedKeepDays
+}
+
+func (c *Cleaner) getAgeBasis(repositoryPolicy *RepositoryPolicy, repositoryTagsMap map[string]string) AgeBasis {
+	if repositoryPolicy != nil && repositoryPolicy.AgeBasis != "" {
+		return repositoryPolicy.AgeBasis
+	}
+	ageBasis := c.config.DefaultAgeBasis
+	if boxCleanerAgeBasisTagValue, ok := repositoryTagsMap[BoxCleanerAgeBasisTag]; ok && AgeBasis(boxCleanerAgeBasisTagValue).IsValid() {
+		ageBasis = AgeBasis(boxCleanerAgeBasisTagValue)
+	}
+	return ageBasis
+}
+
+// getLifecyclePolicy returns the lifecycle policy of the repository policy, the lifecycle policy of the ECR repository (if
+// enabled by the repository policy, by the BoxCleanerEcrLifecyclePolicy repository tag or by default) or the default
+// lifecycle policy, nil means that the other rules are used. The default lifecycle policy is not used if ECR lifecycle
+// policies are explicitly disabled for the repository or if the repository has its own retention settings.
+func (c *Cleaner) getLifecyclePolicy(
+	ctx context.Context,
+	repository types.Repository,
+	repositoryPolicy *RepositoryPolicy,
+	repositoryTagsMap map[string]string,
+) (*LifecyclePolicy, error) {
+
+	if repositoryPolicy != nil && repositoryPolicy.LifecyclePolicy != nil {
+		return repositoryPolicy.LifecyclePolicy, nil
+	}
+
+	ecrLifecyclePolicy := c.config.EcrLifecyclePolicies
+	ecrLifecyclePolicyExplicit := false
+	if boxCleanerEcrLifecyclePolicyTagValueStr, ok := repositoryTagsMap[BoxCleanerEcrLifecyclePolicyTag]; ok {
+		boxCleanerEcrLifecyclePolicyTagValue, err := strconv.ParseBool(boxCleanerEcrLifecyclePolicyTagValueStr)
+		if err == nil {
+			ecrLifecyclePolicy = boxCleanerEcrLifecyclePolicyTagValue
+			ecrLifecyclePolicyExplicit = true
+		}
+	}
+	if repositoryPolicy != nil && repositoryPolicy.EcrLifecyclePolicy != nil {
+		ecrLifecyclePolicy = *repositoryPolicy.EcrLifecyclePolicy
+		ecrLifecyclePolicyExplicit = true
+	}
+
+	if ecrLifecyclePolicy {
+		lifecyclePolicy, err := c.getEcrLifecyclePolicy(ctx, repository)
+		if err != nil || lifecyclePolicy != nil {
+			return lifecyclePolicy, err
+		}
+	} else if ecrLifecyclePolicyExplicit {
+		return nil, nil
+	}
+
+	if hasRetentionSettings(repositoryPolicy, repositoryTagsMap) {
+		return nil, nil
+	}
+
+	return c.config.DefaultLifecyclePolicy, nil
+}
+
+// hasRetentionSettings checks if the repository policy or the repository tags set keep days, keep count, untagged keep
+// days or tag rules, such settings take precedence over the default lifecycle policy.
+func hasRetentionSettings(repositoryPolicy *RepositoryPolicy, repositoryTagsMap map[string]string) bool {
+	if repositoryPolicy != nil {
+		if repositoryPolicy.KeepDays != nil || repositoryPolicy.KeepCount != nil || repositoryPolicy.UntaggedKeepDays != nil ||
+			repositoryPolicy.TagRuleSet != "" || len(repositoryPolicy.TagRules) > 0 {
+			return true
+		}
+	}
+
+	for _, tag := range []string{BoxCleanerKeepDaysTag, BoxCleanerKeepCountTag, BoxCleanerUntaggedKeepDaysTag} {
+		if tagValueStr, ok := repositoryTagsMap[tag]; ok {
+			if _, err := strconv.Atoi(tagValueStr); err == nil {
+				return true
+			}
+		}
+	}
+	_, ok := repositoryTagsMap[BoxCleanerTagRulesTag]
+	return ok
+}
+
+// getTagRules returns tag rules of the repository policy, the tag rule set selected by the repository policy or by the
+// BoxCleanerTagRules repository tag, or the default tag rule set.
+func (c *Cleaner) getTagRules(repositoryPolicy *RepositoryPolicy, repositoryTagsMap map[string]string) ([]TagRule, error) {
+	if repositoryPolicy != nil {
+		if len(repositoryPolicy.TagRules) > 0 {
+			return repositoryPolicy.TagRules, nil
+		}
+		if repositoryPolicy.TagRuleSet != "" {
+			return c.config.Policy.TagRuleSets[repositoryPolicy.TagRuleSet], nil
+		}
+	}
+	if tagRuleSet, ok := repositoryTagsMap[BoxCleanerTagRulesTag]; ok {
+		tagRules, ok := c.config.Policy.TagRuleSets[tagRuleSet]
+		if !ok {
+			return nil, gerrors.Errorf("tag rule set %v does not exist", tagRuleSet)
+		}
+		return tagRules, nil
+	}
+	return c.config.Policy.TagRuleSets[DefaultTagRuleSet], nil
+}
+
 func convertTagsToMap(tags []types.Tag) map[string]string {
 	tagsMap := make(map[string]string, len(tags))
 
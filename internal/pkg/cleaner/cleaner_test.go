@@ -1,6 +1,7 @@
 package cleaner
 
 import (
+	"context"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ecr"
@@ -8,10 +9,20 @@ import (
 	"github.com/aws/smithy-go/ptr"
 	boxaws "github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/aws"
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
 
+const (
+	testHex1 = "1111111111111111111111111111111111111111111111111111111111111111"
+	testHex2 = "2222222222222222222222222222222222222222222222222222222222222222"
+)
+
 func TestCleaner(t *testing.T) {
 	t.Parallel()
 
@@ -19,17 +30,18 @@ func TestCleaner(t *testing.T) {
 		config         Config
 		usedImgs       map[string]struct{}
 		existingImages [][]repositoryData
-	}
-
-	type deleteImageData struct {
-		repositoryName string
-		dockerTag      *string
-		digest         *string
+		// deleteFailures are failure codes returned by BatchDeleteImage for image tags or digests
+		deleteFailures map[string]types.ImageFailureCode
 	}
 
 	tests := map[string]struct {
 		input    testData
 		expected []deleteImageData
+		// expectedBatchSizes are numbers of image ids removed by BatchDeleteImage calls, not checked if nil
+		expectedBatchSizes []int
+		// expectedResult is not checked if nil
+		expectedResult *Result
+		expectedError  bool
 	}{
 		"Found old image": {
 			input: testData{
@@ -266,11 +278,12 @@ func TestCleaner(t *testing.T) {
 				},
 			},
 		},
-		"Multiple images": {
+		"Default keep count": {
 			input: testData{
 				config: Config{
-					DryRun:          false,
-					DefaultKeepDays: 30,
+					DryRun:           false,
+					DefaultKeepDays:  30,
+					DefaultKeepCount: 2,
 				},
 				usedImgs: map[string]struct{}{},
 				existingImages: [][]repositoryData{
@@ -288,36 +301,55 @@ func TestCleaner(t *testing.T) {
 										dockerTags: []string{
 											"v1",
 										},
-										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+										imagePushedAt: testTimeParse(t, "2022-06-01T00:00:00Z"),
 									},
 								},
-							},
-						},
-						{
-							name: "repo2",
-							uri:  "repo2uri",
-							tags: map[string]string{
-								"BoxCleanerEnabled": "true",
-							},
-							images: [][]imageData{
 								{
 									{
-										digest: "v1Digest",
+										digest: "v3Digest",
 										dockerTags: []string{
-											"v1",
+											"v3",
 										},
-										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+										imagePushedAt: testTimeParse(t, "2022-06-03T00:00:00Z"),
+									},
+								},
+								{
+									{
+										digest: "v2Digest",
+										dockerTags: []string{
+											"v2",
+										},
+										imagePushedAt: testTimeParse(t, "2022-06-02T00:00:00Z"),
 									},
 								},
 							},
 						},
 					},
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v1"),
+				},
+			},
+		},
+		"Non default keep count": {
+			input: testData{
+				config: Config{
+					DryRun:           false,
+					DefaultKeepDays:  30,
+					DefaultKeepCount: 2,
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
 					{
 						{
-							name: "repo3",
-							uri:  "repo3uri",
+							name: "repo1",
+							uri:  "repo1uri",
 							tags: map[string]string{
-								"BoxCleanerEnabled": "true",
+								"BoxCleanerEnabled":   "true",
+								"BoxCleanerKeepCount": "1",
 							},
 							images: [][]imageData{
 								{
@@ -326,14 +358,56 @@ func TestCleaner(t *testing.T) {
 										dockerTags: []string{
 											"v1",
 										},
-										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+										imagePushedAt: testTimeParse(t, "2022-06-01T00:00:00Z"),
+									},
+								},
+								{
+									{
+										digest: "v3Digest",
+										dockerTags: []string{
+											"v3",
+										},
+										imagePushedAt: testTimeParse(t, "2022-06-03T00:00:00Z"),
+									},
+								},
+								{
+									{
+										digest: "v2Digest",
+										dockerTags: []string{
+											"v2",
+										},
+										imagePushedAt: testTimeParse(t, "2022-06-02T00:00:00Z"),
 									},
 								},
 							},
 						},
+					},
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v1"),
+				},
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v2"),
+				},
+			},
+		},
+		"Tag rules": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+					Policy:          testParsePolicy(t, `{"tagRuleSets": {"default": [{"tagGlob": "v*.*.*", "neverDelete": true}, {"tagRegex": "pr-\\d+", "keepDays": 3}]}}`),
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
 						{
-							name: "repo4",
-							uri:  "repo4uri",
+							name: "repo1",
+							uri:  "repo1uri",
 							tags: map[string]string{
 								"BoxCleanerEnabled": "true",
 							},
@@ -342,26 +416,46 @@ func TestCleaner(t *testing.T) {
 									{
 										digest: "v1Digest",
 										dockerTags: []string{
-											"v1",
+											"v1.0.0",
 										},
-										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+										imagePushedAt: testTimeParse(t, "2022-06-01T00:00:00Z"),
 									},
 								},
 								{
 									{
-										digest: "v2Digest",
+										digest: "pr1Digest",
 										dockerTags: []string{
-											"v2",
+											"pr-1",
 										},
-										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+										imagePushedAt: testTimeParse(t, "2022-08-29T00:00:00Z"),
+									},
+								},
+								{
+									{
+										digest: "pr2Digest",
+										dockerTags: []string{
+											"pr-2",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-20T00:00:00Z"),
 									},
+								},
+								{
 									{
-										digest: "v3v4Digest",
+										digest: "latestDigest",
 										dockerTags: []string{
-											"v3",
-											"v4",
+											"latest",
 										},
-										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+										imagePushedAt: testTimeParse(t, "2022-08-20T00:00:00Z"),
+									},
+								},
+								{
+									{
+										digest: "v2Digest",
+										dockerTags: []string{
+											"v1.1.0",
+											"pr-3",
+										},
+										imagePushedAt: testTimeParse(t, "2022-06-01T00:00:00Z"),
 									},
 								},
 							},
@@ -372,39 +466,47 @@ func TestCleaner(t *testing.T) {
 			expected: []deleteImageData{
 				{
 					repositoryName: "repo1",
-					dockerTag:      ptr.String("v1"),
-				},
-				{
-					repositoryName: "repo2",
-					dockerTag:      ptr.String("v1"),
-				},
-				{
-					repositoryName: "repo3",
-					dockerTag:      ptr.String("v1"),
-				},
-				{
-					repositoryName: "repo4",
-					dockerTag:      ptr.String("v1"),
-				},
-				{
-					repositoryName: "repo4",
-					dockerTag:      ptr.String("v2"),
+					dockerTag:      ptr.String("pr-2"),
 				},
-				{
-					repositoryName: "repo4",
-					dockerTag:      ptr.String("v3"),
+			},
+		},
+		"Tag rule set selected by repository tag does not exist": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+					Policy:          testParsePolicy(t, `{"tagRuleSets": {"ephemeral": [{"tagGlob": "*", "keepCount": 1}]}}`),
 				},
-				{
-					repositoryName: "repo4",
-					dockerTag:      ptr.String("v4"),
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled":  "true",
+								"BoxCleanerTagRules": "missing",
+							},
+							skipped: true,
+						},
+						testOldImagesRepository(t, "repo2", 1),
+					},
 				},
 			},
+			expected: testOldImageDeletes("repo2", 1),
+			expectedResult: &Result{
+				UnusedImageIds:      1,
+				RemovedImageIds:     1,
+				SkippedRepositories: 1,
+			},
+			expectedError: true,
 		},
-		"Image without tag": {
+		"Tag rules with a tag matching no rule": {
 			input: testData{
 				config: Config{
 					DryRun:          false,
 					DefaultKeepDays: 30,
+					Policy:          testParsePolicy(t, `{"tagRuleSets": {"default": [{"tagRegex": "pr-\\d+", "keepDays": 3}]}}`),
 				},
 				usedImgs: map[string]struct{}{},
 				existingImages: [][]repositoryData{
@@ -418,8 +520,19 @@ func TestCleaner(t *testing.T) {
 							images: [][]imageData{
 								{
 									{
-										digest:        "imageDigest",
-										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+										digest: "pr1Digest",
+										dockerTags: []string{
+											"pr-1",
+											"latest",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-20T00:00:00Z"),
+									},
+									{
+										digest: "pr2Digest",
+										dockerTags: []string{
+											"pr-2",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-20T00:00:00Z"),
 									},
 								},
 							},
@@ -430,32 +543,44 @@ func TestCleaner(t *testing.T) {
 			expected: []deleteImageData{
 				{
 					repositoryName: "repo1",
-					digest:         ptr.String("imageDigest"),
+					dockerTag:      ptr.String("pr-2"),
 				},
 			},
 		},
-		"Found untagged used image by digest": {
+		"Tag rule set selected by repository tag": {
 			input: testData{
 				config: Config{
 					DryRun:          false,
 					DefaultKeepDays: 30,
+					Policy:          testParsePolicy(t, `{"tagRuleSets": {"default": [{"tagGlob": "v*.*.*", "neverDelete": true}], "ephemeral": [{"tagGlob": "*", "keepCount": 1}]}}`),
 				},
-				usedImgs: map[string]struct{}{
-					"repo1uri@imageDigest": {},
-				},
+				usedImgs: map[string]struct{}{},
 				existingImages: [][]repositoryData{
 					{
 						{
 							name: "repo1",
 							uri:  "repo1uri",
 							tags: map[string]string{
-								"BoxCleanerEnabled": "true",
+								"BoxCleanerEnabled":  "true",
+								"BoxCleanerTagRules": "ephemeral",
 							},
 							images: [][]imageData{
 								{
 									{
-										digest:        "imageDigest",
-										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1.0.0",
+										},
+										imagePushedAt: testTimeParse(t, "2022-06-01T00:00:00Z"),
+									},
+								},
+								{
+									{
+										digest: "v2Digest",
+										dockerTags: []string{
+											"v1.1.0",
+										},
+										imagePushedAt: testTimeParse(t, "2022-06-02T00:00:00Z"),
 									},
 								},
 							},
@@ -463,25 +588,88 @@ func TestCleaner(t *testing.T) {
 					},
 				},
 			},
-			expected: []deleteImageData{},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v1.0.0"),
+				},
+			},
 		},
-		"Found tagged used image by digest": {
+		"Policy": {
 			input: testData{
 				config: Config{
 					DryRun:          false,
 					DefaultKeepDays: 30,
+					Policy: testParsePolicy(t, `
+repositories:
+  - selector:
+      namePrefix: team-a/
+    enabled: true
+    keepDays: 10
+    tagRules:
+      - tagGlob: "v*.*.*"
+        neverDelete: true
+  - selector:
+      namePrefix: team-b/
+    enabled: false
+`),
 				},
-				usedImgs: map[string]struct{}{
-					"repo1uri@v1Digest": {},
-				},
+				usedImgs: map[string]struct{}{},
 				existingImages: [][]repositoryData{
 					{
 						{
-							name: "repo1",
-							uri:  "repo1uri",
+							name: "team-a/repo1",
+							uri:  "team-a/repo1uri",
+							tags: map[string]string{
+								"BoxCleanerKeepDays": "60",
+							},
+							enabledByPolicy: ptr.Bool(true),
+							images: [][]imageData{
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-15T00:00:00Z"),
+									},
+								},
+								{
+									{
+										digest: "v2Digest",
+										dockerTags: []string{
+											"v1.0.0",
+										},
+										imagePushedAt: testTimeParse(t, "2022-06-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+						{
+							name: "team-a/repo2",
+							uri:  "team-a/repo2uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "false",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-06-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+						{
+							name: "team-b/repo1",
+							uri:  "team-b/repo1uri",
 							tags: map[string]string{
 								"BoxCleanerEnabled": "true",
 							},
+							enabledByPolicy: ptr.Bool(false),
 							images: [][]imageData{
 								{
 									{
@@ -489,7 +677,25 @@ func TestCleaner(t *testing.T) {
 										dockerTags: []string{
 											"v1",
 										},
-										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+										imagePushedAt: testTimeParse(t, "2022-06-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+						{
+							name: "team-c/repo1",
+							uri:  "team-c/repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-06-01T00:00:00Z"),
 									},
 								},
 							},
@@ -497,13 +703,1488 @@ func TestCleaner(t *testing.T) {
 					},
 				},
 			},
-			expected: []deleteImageData{},
-		},
-	}
-
-	for name, testCase := range tests {
-		// capture range variables
-		name, testCase := name, testCase
+			expected: []deleteImageData{
+				{
+					repositoryName: "team-a/repo1",
+					dockerTag:      ptr.String("v1"),
+				},
+				{
+					repositoryName: "team-c/repo1",
+					dockerTag:      ptr.String("v1"),
+				},
+			},
+		},
+		"Age basis pull": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+					DefaultAgeBasis: AgeBasisPull,
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt:        testTimeParse(t, "2022-06-01T00:00:00Z"),
+										lastRecordedPullTime: aws.Time(testTimeParse(t, "2022-08-20T00:00:00Z")),
+									},
+								},
+								{
+									{
+										digest: "v2Digest",
+										dockerTags: []string{
+											"v2",
+										},
+										imagePushedAt: testTimeParse(t, "2022-06-01T00:00:00Z"),
+									},
+								},
+								{
+									{
+										digest: "v3Digest",
+										dockerTags: []string{
+											"v3",
+										},
+										imagePushedAt:        testTimeParse(t, "2022-06-01T00:00:00Z"),
+										lastRecordedPullTime: aws.Time(testTimeParse(t, "2022-06-15T00:00:00Z")),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v2"),
+				},
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v3"),
+				},
+			},
+		},
+		"Age basis from repository tag": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled":  "true",
+								"BoxCleanerAgeBasis": "pull",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt:        testTimeParse(t, "2022-06-01T00:00:00Z"),
+										lastRecordedPullTime: aws.Time(testTimeParse(t, "2022-08-20T00:00:00Z")),
+									},
+								},
+								{
+									{
+										digest: "v2Digest",
+										dockerTags: []string{
+											"v2",
+										},
+										imagePushedAt: testTimeParse(t, "2022-06-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v2"),
+				},
+			},
+		},
+		"ECR lifecycle policy": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{
+					"repo1uri:v2": {},
+				},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled":            "true",
+								"BoxCleanerEcrLifecyclePolicy": "true",
+							},
+							ecrLifecyclePolicy: aws.String(`{"rules": [{"rulePriority": 1, "selection": {"tagStatus": "any", "countType": "imageCountMoreThan", "countNumber": 1}, "action": {"type": "expire"}}]}`),
+							images: [][]imageData{
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-20T00:00:00Z"),
+									},
+								},
+								{
+									{
+										digest: "v2Digest",
+										dockerTags: []string{
+											"v2",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-10T00:00:00Z"),
+									},
+								},
+								{
+									{
+										digest: "v3Digest",
+										dockerTags: []string{
+											"v3",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-30T00:00:00Z"),
+									},
+								},
+							},
+						},
+						{
+							name: "repo2",
+							uri:  "repo2uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled":            "true",
+								"BoxCleanerEcrLifecyclePolicy": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-20T00:00:00Z"),
+									},
+								},
+								{
+									{
+										digest: "v2Digest",
+										dockerTags: []string{
+											"v2",
+										},
+										imagePushedAt: testTimeParse(t, "2022-06-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v1"),
+				},
+				{
+					repositoryName: "repo2",
+					dockerTag:      ptr.String("v2"),
+				},
+			},
+		},
+		"Default lifecycle policy used only without explicit settings": {
+			input: testData{
+				config: Config{
+					DryRun:                 false,
+					DefaultKeepDays:        30,
+					DefaultLifecyclePolicy: testParseLifecyclePolicy(t, `{"rules": [{"rulePriority": 1, "selection": {"tagStatus": "any", "countType": "imageCountMoreThan", "countNumber": 1}, "action": {"type": "expire"}}]}`),
+					Policy: testParsePolicy(t, `
+repositories:
+  - selector:
+      namePrefix: repo4
+    keepDays: 20
+  - selector:
+      namePrefix: repo5
+    ecrLifecyclePolicy: false
+`),
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						testTwoImagesRepository(t, "repo1", map[string]string{
+							"BoxCleanerEnabled": "true",
+						}),
+						testTwoImagesRepository(t, "repo2", map[string]string{
+							"BoxCleanerEnabled":            "true",
+							"BoxCleanerEcrLifecyclePolicy": "false",
+						}),
+						testTwoImagesRepository(t, "repo3", map[string]string{
+							"BoxCleanerEnabled":  "true",
+							"BoxCleanerKeepDays": "20",
+						}),
+						testTwoImagesRepository(t, "repo4", map[string]string{
+							"BoxCleanerEnabled": "true",
+						}),
+						testTwoImagesRepository(t, "repo5", map[string]string{
+							"BoxCleanerEnabled": "true",
+						}),
+					},
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v1"),
+				},
+			},
+		},
+		"Multiple images": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+						{
+							name: "repo2",
+							uri:  "repo2uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+					{
+						{
+							name: "repo3",
+							uri:  "repo3uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+						{
+							name: "repo4",
+							uri:  "repo4uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+								{
+									{
+										digest: "v2Digest",
+										dockerTags: []string{
+											"v2",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+									{
+										digest: "v3v4Digest",
+										dockerTags: []string{
+											"v3",
+											"v4",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v1"),
+				},
+				{
+					repositoryName: "repo2",
+					dockerTag:      ptr.String("v1"),
+				},
+				{
+					repositoryName: "repo3",
+					dockerTag:      ptr.String("v1"),
+				},
+				{
+					repositoryName: "repo4",
+					dockerTag:      ptr.String("v1"),
+				},
+				{
+					repositoryName: "repo4",
+					dockerTag:      ptr.String("v2"),
+				},
+				{
+					repositoryName: "repo4",
+					dockerTag:      ptr.String("v3"),
+				},
+				{
+					repositoryName: "repo4",
+					dockerTag:      ptr.String("v4"),
+				},
+			},
+		},
+		"Image without tag": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest:        "imageDigest",
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					digest:         ptr.String("imageDigest"),
+				},
+			},
+		},
+		"Untagged images removed immediately": {
+			input: testData{
+				config: Config{
+					DryRun:                  false,
+					DefaultKeepDays:         30,
+					DefaultUntaggedKeepDays: ptr.Int(0),
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest:        "untaggedDigest",
+										imagePushedAt: testTimeParse(t, "2022-08-30T00:00:00Z"),
+									},
+								},
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-30T00:00:00Z"),
+									},
+								},
+								{
+									{
+										digest: "indexDigest",
+										dockerTags: []string{
+											"v2",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-30T00:00:00Z"),
+										childDigests: []string{
+											"childDigest",
+										},
+									},
+								},
+								{
+									{
+										digest:        "childDigest",
+										imagePushedAt: testTimeParse(t, "2022-08-30T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					digest:         ptr.String("untaggedDigest"),
+				},
+			},
+		},
+		"Non default untagged keep days": {
+			input: testData{
+				config: Config{
+					DryRun:                  false,
+					DefaultKeepDays:         30,
+					DefaultUntaggedKeepDays: ptr.Int(0),
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled":          "true",
+								"BoxCleanerUntaggedKeepDays": "7",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest:        "untagged1Digest",
+										imagePushedAt: testTimeParse(t, "2022-08-20T00:00:00Z"),
+									},
+								},
+								{
+									{
+										digest:        "untagged2Digest",
+										imagePushedAt: testTimeParse(t, "2022-08-28T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					digest:         ptr.String("untagged1Digest"),
+				},
+			},
+		},
+		"Found untagged used image by digest": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{
+					"repo1uri@imageDigest": {},
+				},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest:        "imageDigest",
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{},
+		},
+		"Found tagged used image by digest": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{
+					"repo1uri@v1Digest": {},
+				},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{},
+		},
+		"Found used image by another tag": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{
+					"repo1uri:prod": {},
+				},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1",
+											"prod",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+									{
+										digest: "v0Digest",
+										dockerTags: []string{
+											"v0",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v0"),
+				},
+			},
+		},
+		"Found multiple tags image used by digest": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{
+					"repo1uri@v1Digest": {},
+				},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1",
+											"prod",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{},
+		},
+		"Found used image by equivalent reference": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{
+					"123456789012.dkr-ecr.eu-west-1.on.aws/repo1": {},
+				},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "123456789012.dkr.ecr.eu-west-1.amazonaws.com/repo1",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"latest",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{},
+		},
+		"Found young image index": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "indexDigest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-02T00:00:00Z"),
+										childDigests: []string{
+											"amd64Digest",
+											"arm64Digest",
+										},
+									},
+									{
+										digest:        "amd64Digest",
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+									{
+										digest:        "arm64Digest",
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{},
+		},
+		"Found used image index": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{
+					"repo1uri:v1": {},
+				},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "indexDigest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+										childDigests: []string{
+											"amd64Digest",
+											"arm64Digest",
+										},
+									},
+									{
+										digest:        "amd64Digest",
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+									{
+										digest:        "arm64Digest",
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{},
+		},
+		"Found unused image index": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "indexDigest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+										childDigests: []string{
+											"amd64Digest",
+											"arm64Digest",
+										},
+									},
+									{
+										digest:        "amd64Digest",
+										imagePushedAt: testTimeParse(t, "2022-08-02T00:00:00Z"),
+									},
+									{
+										digest:        "arm64Digest",
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v1"),
+				},
+				{
+					repositoryName: "repo1",
+					digest:         ptr.String("amd64Digest"),
+				},
+				{
+					repositoryName: "repo1",
+					digest:         ptr.String("arm64Digest"),
+				},
+			},
+			expectedBatchSizes: []int{1, 2},
+		},
+		"Found child manifest shared by used and unused image indexes": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{
+					"repo1uri:v2": {},
+				},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "index1Digest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+										childDigests: []string{
+											"amd64v1Digest",
+											"arm64Digest",
+										},
+									},
+									{
+										digest: "index2Digest",
+										dockerTags: []string{
+											"v2",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+										childDigests: []string{
+											"amd64v2Digest",
+											"arm64Digest",
+										},
+									},
+									{
+										digest:        "amd64v1Digest",
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+									{
+										digest:        "amd64v2Digest",
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+									{
+										digest:        "arm64Digest",
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v1"),
+				},
+				{
+					repositoryName: "repo1",
+					digest:         ptr.String("amd64v1Digest"),
+				},
+			},
+		},
+		"Found used child manifest of unused image index": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{
+					"repo1uri@amd64Digest": {},
+				},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "indexDigest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+										childDigests: []string{
+											"amd64Digest",
+											"arm64Digest",
+										},
+									},
+									{
+										digest:        "amd64Digest",
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+									{
+										digest:        "arm64Digest",
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v1"),
+				},
+				{
+					repositoryName: "repo1",
+					digest:         ptr.String("arm64Digest"),
+				},
+			},
+		},
+		"Found signature of young image": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "sha256:" + testHex1,
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-02T00:00:00Z"),
+									},
+									{
+										digest: "signature1Digest",
+										dockerTags: []string{
+											"sha256-" + testHex1 + ".sig",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+									{
+										digest: "attestation1Digest",
+										dockerTags: []string{
+											"sha256-" + testHex1 + ".att",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{},
+		},
+		"Found signature of unused image": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "sha256:" + testHex1,
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+									{
+										digest: "signature1Digest",
+										dockerTags: []string{
+											"sha256-" + testHex1 + ".sig",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-02T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v1"),
+				},
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("sha256-" + testHex1 + ".sig"),
+				},
+			},
+		},
+		"Found signature without subject image": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "signature1Digest",
+										dockerTags: []string{
+											"sha256-" + testHex1 + ".sig",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+									{
+										digest: "signature2Digest",
+										dockerTags: []string{
+											"sha256-" + testHex2 + ".sig",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-02T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("sha256-" + testHex1 + ".sig"),
+				},
+			},
+		},
+		"Found OCI referrers": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{
+					"repo1uri:v1": {},
+				},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+									{
+										digest: "v2Digest",
+										dockerTags: []string{
+											"v2",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+									{
+										digest:        "sbom1Digest",
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+										subjectDigest: "v1Digest",
+									},
+									{
+										digest:        "sbom2Digest",
+										imagePushedAt: testTimeParse(t, "2022-08-02T00:00:00Z"),
+										subjectDigest: "v2Digest",
+									},
+									{
+										digest:        "sbom3Digest",
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+										subjectDigest: "missingDigest",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v2"),
+				},
+				{
+					repositoryName: "repo1",
+					digest:         ptr.String("sbom3Digest"),
+				},
+				{
+					repositoryName: "repo1",
+					digest:         ptr.String("sbom2Digest"),
+				},
+			},
+			expectedBatchSizes: []int{2, 1},
+		},
+		"Removals are batched": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								testOldImages(t, 250),
+							},
+						},
+					},
+				},
+			},
+			expected:           testOldImageDeletes("repo1", 250),
+			expectedBatchSizes: []int{100, 100, 50},
+			expectedResult: &Result{
+				UnusedImageIds:  250,
+				RemovedImageIds: 250,
+			},
+		},
+		"Repositories processed concurrently": {
+			input: testData{
+				config: Config{
+					DryRun:                false,
+					DefaultKeepDays:       30,
+					RepositoryConcurrency: 3,
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						testOldImagesRepository(t, "repo1", 2),
+						testOldImagesRepository(t, "repo2", 3),
+					},
+					{
+						testOldImagesRepository(t, "repo3", 1),
+						testOldImagesRepository(t, "repo4", 2),
+						testOldImagesRepository(t, "repo5", 150),
+					},
+				},
+			},
+			expected: append(append(append(append(
+				testOldImageDeletes("repo1", 2),
+				testOldImageDeletes("repo2", 3)...),
+				testOldImageDeletes("repo3", 1)...),
+				testOldImageDeletes("repo4", 2)...),
+				testOldImageDeletes("repo5", 150)...),
+			expectedResult: &Result{
+				UnusedImageIds:  158,
+				RemovedImageIds: 158,
+			},
+		},
+		"Image referenced by an image index is kept with its dependents": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest:        "sha256:" + testHex1,
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+									{
+										digest: "signature1Digest",
+										dockerTags: []string{
+											"sha256-" + testHex1 + ".sig",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+									{
+										digest: "v2Digest",
+										dockerTags: []string{
+											"v2",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+				deleteFailures: map[string]types.ImageFailureCode{
+					"sha256:" + testHex1: types.ImageFailureCodeImageReferencedByManifestList,
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					digest:         ptr.String("sha256:" + testHex1),
+				},
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v2"),
+				},
+			},
+			expectedResult: &Result{
+				UnusedImageIds:  2,
+				RemovedImageIds: 1,
+				KeptImageIds:    1,
+			},
+		},
+		"Image removed in the meantime": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+				deleteFailures: map[string]types.ImageFailureCode{
+					"v1": types.ImageFailureCodeImageNotFound,
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v1"),
+				},
+			},
+			expectedResult: &Result{
+				UnusedImageIds:   1,
+				NotFoundImageIds: 1,
+			},
+		},
+		"Image removal failure does not stop other removals": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "v1Digest",
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+						{
+							name: "repo2",
+							uri:  "repo2uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "v2Digest",
+										dockerTags: []string{
+											"v2",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+				deleteFailures: map[string]types.ImageFailureCode{
+					"v1": types.ImageFailureCodeKmsError,
+				},
+			},
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v1"),
+				},
+				{
+					repositoryName: "repo2",
+					dockerTag:      ptr.String("v2"),
+				},
+			},
+			expectedResult: &Result{
+				UnusedImageIds:  2,
+				RemovedImageIds: 1,
+				FailedImageIds:  1,
+			},
+			expectedError: true,
+		},
+		"Signature of an image that failed to be removed is kept": {
+			input: testData{
+				config: Config{
+					DryRun:          false,
+					DefaultKeepDays: 30,
+				},
+				usedImgs: map[string]struct{}{},
+				existingImages: [][]repositoryData{
+					{
+						{
+							name: "repo1",
+							uri:  "repo1uri",
+							tags: map[string]string{
+								"BoxCleanerEnabled": "true",
+							},
+							images: [][]imageData{
+								{
+									{
+										digest: "sha256:" + testHex1,
+										dockerTags: []string{
+											"v1",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+									},
+									{
+										digest: "signature1Digest",
+										dockerTags: []string{
+											"sha256-" + testHex1 + ".sig",
+										},
+										imagePushedAt: testTimeParse(t, "2022-08-02T00:00:00Z"),
+									},
+								},
+							},
+						},
+					},
+				},
+				deleteFailures: map[string]types.ImageFailureCode{
+					"v1": types.ImageFailureCodeKmsError,
+				},
+			},
+			// the signature is never sent to BatchDeleteImage
+			expected: []deleteImageData{
+				{
+					repositoryName: "repo1",
+					dockerTag:      ptr.String("v1"),
+				},
+			},
+			expectedResult: &Result{
+				UnusedImageIds: 1,
+				FailedImageIds: 1,
+			},
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
@@ -515,40 +2196,108 @@ func TestCleaner(t *testing.T) {
 
 			mockExistingImages(ctrl, mockAwsProvider, testCase.input.existingImages)
 
-			for _, expectedDelete := range testCase.expected {
-				mockAwsProvider.MockEcrClient.EXPECT().BatchDeleteImage(gomock.Any(), &ecr.BatchDeleteImageInput{
-					ImageIds: []types.ImageIdentifier{
-						{
-							ImageTag:    expectedDelete.dockerTag,
-							ImageDigest: expectedDelete.digest,
-						},
-					},
-					RepositoryName: aws.String(expectedDelete.repositoryName),
-				}).Return(&ecr.BatchDeleteImageOutput{}, nil)
-			}
+			// repositories can be processed concurrently
+			var deletesLock sync.Mutex
+			var deletes []deleteImageData
+			var batchSizes []int
+			mockAwsProvider.MockEcrClient.EXPECT().BatchDeleteImage(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, params *ecr.BatchDeleteImageInput, optFns ...func(*ecr.Options)) (*ecr.BatchDeleteImageOutput, error) {
+					deletesLock.Lock()
+					defer deletesLock.Unlock()
+
+					for _, imageId := range params.ImageIds {
+						deletes = append(deletes, deleteImageData{
+							repositoryName: *params.RepositoryName,
+							dockerTag:      imageId.ImageTag,
+							digest:         imageId.ImageDigest,
+						})
+					}
+					batchSizes = append(batchSizes, len(params.ImageIds))
+
+					var failures []types.ImageFailure
+					for _, imageId := range params.ImageIds {
+						if failureCode, ok := testCase.input.deleteFailures[aws.ToString(imageId.ImageTag)+aws.ToString(imageId.ImageDigest)]; ok {
+							// capture range variables
+							imageId := imageId
+
+							failures = append(failures, types.ImageFailure{
+								FailureCode:   failureCode,
+								FailureReason: aws.String("test failure"),
+								ImageId:       &imageId,
+							})
+						}
+					}
+					return &ecr.BatchDeleteImageOutput{
+						Failures: failures,
+					}, nil
+				}).AnyTimes()
 
-			err := (&Cleaner{
+			result, err := (&Cleaner{
 				awsProvider: mockAwsProvider.Provider,
 				config:      testCase.input.config,
-			}).Clean(startTime)
-			if err != nil {
+			}).Clean(context.TODO(), startTime)
+			if testCase.expectedError {
+				if err == nil {
+					t.Error("Expected error")
+				}
+			} else if err != nil {
 				t.Fatal(err)
 			}
+
+			// the order of removals is checked within repositories only
+			sort.SliceStable(deletes, func(i, j int) bool {
+				return deletes[i].repositoryName < deletes[j].repositoryName
+			})
+			diff := cmp.Diff(testCase.expected, deletes, cmp.AllowUnexported(deleteImageData{}), cmpopts.EquateEmpty())
+			if diff != "" {
+				t.Error(diff)
+			}
+			if testCase.expectedBatchSizes != nil {
+				diff := cmp.Diff(testCase.expectedBatchSizes, batchSizes)
+				if diff != "" {
+					t.Error(diff)
+				}
+			}
+			if testCase.expectedResult != nil {
+				diff := cmp.Diff(*testCase.expectedResult, result)
+				if diff != "" {
+					t.Error(diff)
+				}
+			}
 		})
 	}
 }
 
+type deleteImageData struct {
+	repositoryName string
+	dockerTag      *string
+	digest         *string
+}
+
 type imageData struct {
 	digest        string
 	dockerTags    []string
 	imagePushedAt time.Time
+	// lastRecordedPullTime is not set if the image has never been pulled
+	lastRecordedPullTime *time.Time
+	// childDigests makes the image an image index with these child manifests
+	childDigests []string
+	// subjectDigest makes the image an OCI artifact referring to this subject
+	subjectDigest string
 }
 
 type repositoryData struct {
-	name   string
-	uri    string
-	tags   map[string]string
-	images [][]imageData
+	name string
+	uri  string
+	tags map[string]string
+	// enabledByPolicy overrides the BoxCleanerEnabled tag if set
+	enabledByPolicy *bool
+	// ecrLifecyclePolicy is the lifecycle policy of the repository, returned if the BoxCleanerEcrLifecyclePolicy tag is
+	// set to true
+	ecrLifecyclePolicy *string
+	// skipped is set if the repository is skipped because of invalid settings, so its images are not listed
+	skipped bool
+	images  [][]imageData
 }
 
 func mockExistingImages(ctrl *gomock.Controller, mockAwsProvider *boxaws.MockProvider, existingImages [][]repositoryData) {
@@ -585,22 +2334,74 @@ func mockExistingImages(ctrl *gomock.Controller, mockAwsProvider *boxaws.MockPro
 				Tags: tags,
 			}, nil)
 
-			if tag, ok := repoData.tags["BoxCleanerEnabled"]; ok && tag == "true" {
+			enabled := repoData.tags["BoxCleanerEnabled"] == "true"
+			if repoData.enabledByPolicy != nil {
+				enabled = *repoData.enabledByPolicy
+			}
+			if enabled && !repoData.skipped {
+				if repoData.tags["BoxCleanerEcrLifecyclePolicy"] == "true" {
+					getLifecyclePolicyCall := mockAwsProvider.MockEcrClient.EXPECT().GetLifecyclePolicy(gomock.Any(), &ecr.GetLifecyclePolicyInput{
+						RepositoryName: aws.String(repoData.name),
+					})
+					if repoData.ecrLifecyclePolicy != nil {
+						getLifecyclePolicyCall.Return(&ecr.GetLifecyclePolicyOutput{
+							LifecyclePolicyText: repoData.ecrLifecyclePolicy,
+						}, nil)
+					} else {
+						getLifecyclePolicyCall.Return(nil, &types.LifecyclePolicyNotFoundException{})
+					}
+				}
+
 				mockDescribeImagesPaginator := boxaws.NewMockEcrDescribeImagesPaginator(ctrl)
 				mockAwsProvider.MockEcrPaginators.EXPECT().NewDescribeImagesPaginator(&ecr.DescribeImagesInput{
 					RepositoryName: aws.String(repoData.name),
 				}).Return(mockDescribeImagesPaginator)
 
+				var indexIds []types.ImageIdentifier
+				var indexes []types.Image
+				var artifactIds []types.ImageIdentifier
+				var artifacts []types.Image
 				for _, imagesPage := range repoData.images {
 					mockDescribeImagesPaginator.EXPECT().HasMorePages().Return(true)
 
 					ecrImages := make([]types.ImageDetail, len(imagesPage))
 					for j, image := range imagesPage {
 						ecrImages[j] = types.ImageDetail{
-							ImagePushedAt:  aws.Time(image.imagePushedAt),
-							ImageDigest:    aws.String(image.digest),
-							ImageTags:      image.dockerTags,
-							RepositoryName: aws.String(repoData.name),
+							ImagePushedAt:        aws.Time(image.imagePushedAt),
+							LastRecordedPullTime: image.lastRecordedPullTime,
+							ImageDigest:          aws.String(image.digest),
+							ImageTags:            image.dockerTags,
+							RepositoryName:       aws.String(repoData.name),
+						}
+
+						if image.childDigests != nil {
+							ecrImages[j].ImageManifestMediaType = aws.String(OciImageIndexMediaType)
+
+							indexIds = append(indexIds, types.ImageIdentifier{
+								ImageDigest: aws.String(image.digest),
+							})
+							indexes = append(indexes, types.Image{
+								ImageId: &types.ImageIdentifier{
+									ImageDigest: aws.String(image.digest),
+								},
+								ImageManifest: aws.String(testImageIndexManifest(image.childDigests)),
+							})
+						}
+
+						if image.subjectDigest != "" {
+							ecrImages[j].ImageManifestMediaType = aws.String(OciImageManifestMediaType)
+							ecrImages[j].ArtifactMediaType = aws.String("application/vnd.example.sbom.v1+json")
+
+							artifactIds = append(artifactIds, types.ImageIdentifier{
+								ImageDigest: aws.String(image.digest),
+							})
+							artifacts = append(artifacts, types.Image{
+								ImageId: &types.ImageIdentifier{
+									ImageDigest: aws.String(image.digest),
+								},
+								ImageManifest: aws.String(fmt.Sprintf(`{"schemaVersion": 2, "mediaType": "%v", "subject": {"digest": "%v"}}`,
+									OciImageManifestMediaType, image.subjectDigest)),
+							})
 						}
 					}
 
@@ -609,12 +2410,116 @@ func mockExistingImages(ctrl *gomock.Controller, mockAwsProvider *boxaws.MockPro
 					}, nil)
 				}
 				mockDescribeImagesPaginator.EXPECT().HasMorePages().Return(false)
+
+				if len(indexIds) > 0 {
+					mockAwsProvider.MockEcrClient.EXPECT().BatchGetImage(gomock.Any(), &ecr.BatchGetImageInput{
+						RepositoryName:     aws.String(repoData.name),
+						ImageIds:           indexIds,
+						AcceptedMediaTypes: []string{OciImageIndexMediaType, DockerManifestListMediaType},
+					}).Return(&ecr.BatchGetImageOutput{
+						Images: indexes,
+					}, nil)
+				}
+
+				if len(artifactIds) > 0 {
+					mockAwsProvider.MockEcrClient.EXPECT().BatchGetImage(gomock.Any(), &ecr.BatchGetImageInput{
+						RepositoryName:     aws.String(repoData.name),
+						ImageIds:           artifactIds,
+						AcceptedMediaTypes: []string{OciImageManifestMediaType},
+					}).Return(&ecr.BatchGetImageOutput{
+						Images: artifacts,
+					}, nil)
+				}
 			}
 		}
 	}
 	mockEcrDescribeResourcesPaginator.EXPECT().HasMorePages().Return(false)
 }
 
+func testImageIndexManifest(childDigests []string) string {
+	manifests := make([]string, 0, len(childDigests))
+	for _, childDigest := range childDigests {
+		manifests = append(manifests, fmt.Sprintf(`{"mediaType": "application/vnd.oci.image.manifest.v1+json", "digest": "%v"}`, childDigest))
+	}
+	return fmt.Sprintf(`{"schemaVersion": 2, "mediaType": "%v", "manifests": [%v]}`,
+		OciImageIndexMediaType, strings.Join(manifests, ", "))
+}
+
+func testOldImages(t *testing.T, count int) []imageData {
+	images := make([]imageData, 0, count)
+	for i := 0; i < count; i++ {
+		images = append(images, imageData{
+			digest:        fmt.Sprintf("v%vDigest", i),
+			dockerTags:    []string{fmt.Sprintf("v%v", i)},
+			imagePushedAt: testTimeParse(t, "2022-08-01T00:00:00Z"),
+		})
+	}
+	return images
+}
+
+func testOldImagesRepository(t *testing.T, name string, imageCount int) repositoryData {
+	return repositoryData{
+		name: name,
+		uri:  name + "uri",
+		tags: map[string]string{
+			"BoxCleanerEnabled": "true",
+		},
+		images: [][]imageData{
+			testOldImages(t, imageCount),
+		},
+	}
+}
+
+func testOldImageDeletes(repositoryName string, count int) []deleteImageData {
+	deletes := make([]deleteImageData, 0, count)
+	for i := 0; i < count; i++ {
+		deletes = append(deletes, deleteImageData{
+			repositoryName: repositoryName,
+			dockerTag:      ptr.String(fmt.Sprintf("v%v", i)),
+		})
+	}
+	return deletes
+}
+
+// testTwoImagesRepository returns a repository with images pushed 11 days (v1) and 1 day (v2) before the start time.
+func testTwoImagesRepository(t *testing.T, name string, tags map[string]string) repositoryData {
+	return repositoryData{
+		name: name,
+		uri:  name + "uri",
+		tags: tags,
+		images: [][]imageData{
+			{
+				{
+					digest:        "v1Digest",
+					dockerTags:    []string{"v1"},
+					imagePushedAt: testTimeParse(t, "2022-08-20T00:00:00Z"),
+				},
+				{
+					digest:        "v2Digest",
+					dockerTags:    []string{"v2"},
+					imagePushedAt: testTimeParse(t, "2022-08-30T00:00:00Z"),
+				},
+			},
+		},
+	}
+}
+
+func testParseLifecyclePolicy(t *testing.T, data string) *LifecyclePolicy {
+	lifecyclePolicy, err := ParseLifecyclePolicy([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lifecyclePolicy
+}
+
+func testParsePolicy(t *testing.T, data string) Policy {
+	policy, err := ParsePolicy([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return policy
+}
+
 func testTimeParse(t *testing.T, timeStr string) time.Time {
 	startTime, err := time.Parse(time.RFC3339, timeStr)
 	if err != nil {
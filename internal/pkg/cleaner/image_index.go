@@ -0,0 +1,101 @@
+package cleaner
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	gerrors "github.com/pkg/errors"
+)
+
+const (
+	OciImageIndexMediaType           = "application/vnd.oci.image.index.v1+json"
+	DockerManifestListMediaType      = "application/vnd.docker.distribution.manifest.list.v2+json"
+	batchGetImageMaxImageIdentifiers = 100
+)
+
+// imageIndexManifest is the part of an OCI image index (or a Docker manifest list) needed to find its child manifests.
+type imageIndexManifest struct {
+	Manifests []struct {
+		Digest string `json:"digest"`
+	} `json:"manifests"`
+}
+
+func isImageIndex(image types.ImageDetail) bool {
+	if image.ImageManifestMediaType == nil {
+		return false
+	}
+	mediaType := *image.ImageManifestMediaType
+	return mediaType == OciImageIndexMediaType || mediaType == DockerManifestListMediaType
+}
+
+// getImageIndexChildren returns digests of child manifests (e.g. per-platform images of a multi-architecture image)
+// of all image indexes in the repository, by the digest of the index.
+func (c *Cleaner) getImageIndexChildren(ctx context.Context, repository types.Repository, images []types.ImageDetail) (map[string][]string, error) {
+	var indexDigests []string
+	for _, image := range images {
+		if isImageIndex(image) {
+			indexDigests = append(indexDigests, *image.ImageDigest)
+		}
+	}
+
+	indexes, err := c.batchGetImages(ctx, repository, indexDigests, []string{OciImageIndexMediaType, DockerManifestListMediaType})
+	if err != nil {
+		return nil, gerrors.Wrapf(err, "cannot get image indexes")
+	}
+
+	children := make(map[string][]string, len(indexes))
+	for _, index := range indexes {
+		var manifest imageIndexManifest
+		err := json.Unmarshal([]byte(aws.ToString(index.ImageManifest)), &manifest)
+		if err != nil {
+			return nil, gerrors.Wrapf(err, "cannot parse image index %v", *index.ImageId.ImageDigest)
+		}
+
+		for _, childManifest := range manifest.Manifests {
+			children[*index.ImageId.ImageDigest] = append(children[*index.ImageId.ImageDigest], childManifest.Digest)
+		}
+	}
+
+	return children, nil
+}
+
+// batchGetImages gets manifests of all images with given digests. Missing manifests are reported as an error, as
+// relations between images would be incomplete (and e.g. an unknown child manifest would be removed).
+func (c *Cleaner) batchGetImages(ctx context.Context, repository types.Repository, digests []string, acceptedMediaTypes []string) ([]types.Image, error) {
+	ecrClient := c.awsProvider.EcrClient
+
+	var images []types.Image
+	for start := 0; start < len(digests); start += batchGetImageMaxImageIdentifiers {
+		end := start + batchGetImageMaxImageIdentifiers
+		if end > len(digests) {
+			end = len(digests)
+		}
+
+		imageIds := make([]types.ImageIdentifier, 0, end-start)
+		for _, digest := range digests[start:end] {
+			imageIds = append(imageIds, types.ImageIdentifier{
+				ImageDigest: aws.String(digest),
+			})
+		}
+
+		batchGetImageOutput, err := ecrClient.BatchGetImage(ctx, &ecr.BatchGetImageInput{
+			RepositoryName:     repository.RepositoryName,
+			ImageIds:           imageIds,
+			AcceptedMediaTypes: acceptedMediaTypes,
+		})
+		if err != nil {
+			return nil, gerrors.Wrapf(err, "cannot batch get images")
+		}
+
+		if len(batchGetImageOutput.Failures) > 0 {
+			failure := batchGetImageOutput.Failures[0]
+			return nil, gerrors.Errorf("cannot get image: %v %v", failure.FailureCode, aws.ToString(failure.FailureReason))
+		}
+
+		images = append(images, batchGetImageOutput.Images...)
+	}
+
+	return images, nil
+}
@@ -0,0 +1,292 @@
+package cleaner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/hashicorp/go-hclog"
+	gerrors "github.com/pkg/errors"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	lifecyclePolicyTagStatusTagged   = "tagged"
+	lifecyclePolicyTagStatusUntagged = "untagged"
+	lifecyclePolicyTagStatusAny      = "any"
+
+	lifecyclePolicyCountTypeImageCountMoreThan = "imageCountMoreThan"
+	lifecyclePolicyCountTypeSinceImagePushed   = "sinceImagePushed"
+	lifecyclePolicyCountUnitDays               = "days"
+
+	lifecyclePolicyActionTypeExpire = "expire"
+)
+
+// LifecyclePolicy is an ECR lifecycle policy document, evaluated by ECR cleaner itself, so images in use are never
+// expired.
+type LifecyclePolicy struct {
+	Rules []LifecyclePolicyRule `json:"rules"`
+}
+
+type LifecyclePolicyRule struct {
+	RulePriority int                      `json:"rulePriority"`
+	Description  string                   `json:"description,omitempty"`
+	Selection    LifecyclePolicySelection `json:"selection"`
+	Action       LifecyclePolicyAction    `json:"action"`
+}
+
+type LifecyclePolicySelection struct {
+	TagStatus      string   `json:"tagStatus"`
+	TagPrefixList  []string `json:"tagPrefixList,omitempty"`
+	TagPatternList []string `json:"tagPatternList,omitempty"`
+	CountType      string   `json:"countType"`
+	CountUnit      string   `json:"countUnit,omitempty"`
+	CountNumber    int      `json:"countNumber"`
+
+	tagPatternRegexps []*regexp.Regexp
+}
+
+type LifecyclePolicyAction struct {
+	Type string `json:"type"`
+}
+
+// LoadLifecyclePolicy reads and validates a lifecycle policy from a JSON file.
+func LoadLifecyclePolicy(fileName string) (*LifecyclePolicy, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, gerrors.Wrapf(err, "cannot read lifecycle policy file %v", fileName)
+	}
+
+	lifecyclePolicy, err := ParseLifecyclePolicy(data)
+	if err != nil {
+		return nil, gerrors.Wrapf(err, "invalid lifecycle policy file %v", fileName)
+	}
+	return lifecyclePolicy, nil
+}
+
+// ParseLifecyclePolicy parses and validates a lifecycle policy. Unknown fields and values are reported as errors, so
+// rules that ECR cleaner does not understand are never evaluated differently than by ECR.
+func ParseLifecyclePolicy(data []byte) (*LifecyclePolicy, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var lifecyclePolicy LifecyclePolicy
+	err := decoder.Decode(&lifecyclePolicy)
+	if err != nil {
+		return nil, gerrors.Wrapf(err, "cannot parse lifecycle policy")
+	}
+
+	err = lifecyclePolicy.compile()
+	if err != nil {
+		return nil, err
+	}
+	return &lifecyclePolicy, nil
+}
+
+// compile validates the lifecycle policy the same way as ECR does and sorts its rules by priority.
+func (p *LifecyclePolicy) compile() error {
+	if len(p.Rules) == 0 {
+		return gerrors.New("lifecycle policy has to contain at least one rule")
+	}
+
+	sort.SliceStable(p.Rules, func(i, j int) bool {
+		return p.Rules[i].RulePriority < p.Rules[j].RulePriority
+	})
+
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if rule.RulePriority < 1 {
+			return gerrors.Errorf("rulePriority %v has to be positive", rule.RulePriority)
+		}
+		if i > 0 && p.Rules[i-1].RulePriority == rule.RulePriority {
+			return gerrors.Errorf("rulePriority %v is not unique", rule.RulePriority)
+		}
+
+		err := rule.compile()
+		if err != nil {
+			return gerrors.Wrapf(err, "invalid rule with rulePriority %v", rule.RulePriority)
+		}
+
+		if rule.Selection.TagStatus == lifecyclePolicyTagStatusAny && i != len(p.Rules)-1 {
+			return gerrors.Errorf("rule with tagStatus any has to have the highest rulePriority")
+		}
+	}
+	return nil
+}
+
+func (r *LifecyclePolicyRule) compile() error {
+	if r.Action.Type != lifecyclePolicyActionTypeExpire {
+		return gerrors.Errorf("unsupported action type %v", r.Action.Type)
+	}
+
+	selection := &r.Selection
+	switch selection.TagStatus {
+	case lifecyclePolicyTagStatusTagged:
+		if len(selection.TagPrefixList) == 0 && len(selection.TagPatternList) == 0 {
+			return gerrors.New("tagPrefixList or tagPatternList is required for tagStatus tagged")
+		}
+		if len(selection.TagPrefixList) > 0 && len(selection.TagPatternList) > 0 {
+			return gerrors.New("tagPrefixList and tagPatternList cannot be used together")
+		}
+	case lifecyclePolicyTagStatusUntagged, lifecyclePolicyTagStatusAny:
+		if len(selection.TagPrefixList) > 0 || len(selection.TagPatternList) > 0 {
+			return gerrors.Errorf("tagPrefixList and tagPatternList cannot be used with tagStatus %v", selection.TagStatus)
+		}
+	default:
+		return gerrors.Errorf("unsupported tagStatus %v", selection.TagStatus)
+	}
+
+	switch selection.CountType {
+	case lifecyclePolicyCountTypeImageCountMoreThan:
+		if selection.CountUnit != "" {
+			return gerrors.Errorf("countUnit cannot be used with countType %v", selection.CountType)
+		}
+	case lifecyclePolicyCountTypeSinceImagePushed:
+		if selection.CountUnit != lifecyclePolicyCountUnitDays {
+			return gerrors.Errorf("unsupported countUnit %v", selection.CountUnit)
+		}
+	default:
+		return gerrors.Errorf("unsupported countType %v", selection.CountType)
+	}
+	if selection.CountNumber < 1 {
+		return gerrors.Errorf("countNumber %v has to be positive", selection.CountNumber)
+	}
+
+	selection.tagPatternRegexps = nil
+	for _, tagPattern := range selection.TagPatternList {
+		// * is the only wildcard, it matches zero or more characters
+		quotedTagPattern := strings.ReplaceAll(regexp.QuoteMeta(tagPattern), `\*`, ".*")
+		selection.tagPatternRegexps = append(selection.tagPatternRegexps, regexp.MustCompile("^"+quotedTagPattern+"$"))
+	}
+	return nil
+}
+
+// matches checks if the image is selected by the rule. With multiple prefixes (or patterns) only images having tags
+// matching all of them are selected.
+func (s *LifecyclePolicySelection) matches(image types.ImageDetail) bool {
+	switch s.TagStatus {
+	case lifecyclePolicyTagStatusUntagged:
+		return len(image.ImageTags) == 0
+	case lifecyclePolicyTagStatusAny:
+		return true
+	}
+
+	if len(image.ImageTags) == 0 {
+		return false
+	}
+	for _, tagPrefix := range s.TagPrefixList {
+		if !anyTagMatches(image.ImageTags, func(imageTag string) bool { return strings.HasPrefix(imageTag, tagPrefix) }) {
+			return false
+		}
+	}
+	for _, tagPatternRegexp := range s.tagPatternRegexps {
+		if !anyTagMatches(image.ImageTags, tagPatternRegexp.MatchString) {
+			return false
+		}
+	}
+	return true
+}
+
+func anyTagMatches(imageTags []string, matches func(imageTag string) bool) bool {
+	for _, imageTag := range imageTags {
+		if matches(imageTag) {
+			return true
+		}
+	}
+	return false
+}
+
+// getLifecycleRetainedImageDigests returns digests of images not expired by the lifecycle policy. Every image is
+// handled by the first rule (by priority) selecting it, rules with a lower priority cannot expire it. Dependents are
+// not checked (they are kept together with the images they depend on).
+func getLifecycleRetainedImageDigests(
+	logger hclog.Logger,
+	repository types.Repository,
+	images []types.ImageDetail,
+	dependentDigests map[string]struct{},
+	lifecyclePolicy *LifecyclePolicy,
+	startTime time.Time,
+) map[string]struct{} {
+
+	retainedImageDigests := make(map[string]struct{})
+	var remainingImages []types.ImageDetail
+	for _, image := range images {
+		if _, isDependent := dependentDigests[*image.ImageDigest]; !isDependent {
+			retainedImageDigests[*image.ImageDigest] = struct{}{}
+			remainingImages = append(remainingImages, image)
+		}
+	}
+
+	for _, rule := range lifecyclePolicy.Rules {
+		var selectedImages []types.ImageDetail
+		var notSelectedImages []types.ImageDetail
+		for _, image := range remainingImages {
+			if rule.Selection.matches(image) {
+				selectedImages = append(selectedImages, image)
+			} else {
+				notSelectedImages = append(notSelectedImages, image)
+			}
+		}
+		remainingImages = notSelectedImages
+
+		for _, image := range getLifecycleExpiredImages(rule.Selection, selectedImages, startTime) {
+			logger.Debug("Found image expired by lifecycle policy", "repository", *repository.RepositoryUri,
+				"imageDigest", *image.ImageDigest, "rulePriority", rule.RulePriority)
+
+			delete(retainedImageDigests, *image.ImageDigest)
+		}
+	}
+
+	return retainedImageDigests
+}
+
+func getLifecycleExpiredImages(selection LifecyclePolicySelection, images []types.ImageDetail, startTime time.Time) []types.ImageDetail {
+	switch selection.CountType {
+	case lifecyclePolicyCountTypeImageCountMoreThan:
+		sort.SliceStable(images, func(i, j int) bool {
+			return images[i].ImagePushedAt.After(*images[j].ImagePushedAt)
+		})
+		if len(images) <= selection.CountNumber {
+			return nil
+		}
+		return images[selection.CountNumber:]
+
+	case lifecyclePolicyCountTypeSinceImagePushed:
+		var expiredImages []types.ImageDetail
+		for _, image := range images {
+			imageAgeDays := startTime.Sub(*image.ImagePushedAt).Hours() / 24
+			if imageAgeDays > float64(selection.CountNumber) {
+				expiredImages = append(expiredImages, image)
+			}
+		}
+		return expiredImages
+	}
+	return nil
+}
+
+// getEcrLifecyclePolicy returns the lifecycle policy of the repository or nil if the repository does not have one.
+func (c *Cleaner) getEcrLifecyclePolicy(ctx context.Context, repository types.Repository) (*LifecyclePolicy, error) {
+	ecrClient := c.awsProvider.EcrClient
+
+	getLifecyclePolicyOutput, err := ecrClient.GetLifecyclePolicy(ctx, &ecr.GetLifecyclePolicyInput{
+		RepositoryName: repository.RepositoryName,
+	})
+	if err != nil {
+		var lifecyclePolicyNotFoundException *types.LifecyclePolicyNotFoundException
+		if gerrors.As(err, &lifecyclePolicyNotFoundException) {
+			return nil, nil
+		}
+		return nil, gerrors.Wrapf(err, "cannot get lifecycle policy of repository %v", *repository.RepositoryName)
+	}
+
+	lifecyclePolicy, err := ParseLifecyclePolicy([]byte(*getLifecyclePolicyOutput.LifecyclePolicyText))
+	if err != nil {
+		return nil, gerrors.Wrapf(err, "invalid lifecycle policy of repository %v", *repository.RepositoryName)
+	}
+	return lifecyclePolicy, nil
+}
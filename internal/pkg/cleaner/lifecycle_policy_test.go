@@ -0,0 +1,246 @@
+package cleaner
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+)
+
+func TestParseLifecyclePolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		data          string
+		expectedError bool
+	}{
+		"Valid policy": {
+			data: `{"rules": [
+				{"rulePriority": 2, "selection": {"tagStatus": "any", "countType": "imageCountMoreThan", "countNumber": 10},
+					"action": {"type": "expire"}},
+				{"rulePriority": 1, "description": "Expire untagged images", "selection": {"tagStatus": "untagged",
+					"countType": "sinceImagePushed", "countUnit": "days", "countNumber": 1}, "action": {"type": "expire"}}
+			]}`,
+		},
+		"Tag prefixes and patterns": {
+			data: `{"rules": [
+				{"rulePriority": 1, "selection": {"tagStatus": "tagged", "tagPrefixList": ["prod"],
+					"countType": "imageCountMoreThan", "countNumber": 10}, "action": {"type": "expire"}},
+				{"rulePriority": 2, "selection": {"tagStatus": "tagged", "tagPatternList": ["*-rc*"],
+					"countType": "imageCountMoreThan", "countNumber": 1}, "action": {"type": "expire"}}
+			]}`,
+		},
+		"No rules": {
+			data:          `{"rules": []}`,
+			expectedError: true,
+		},
+		"Invalid JSON": {
+			data:          `{"rules": [`,
+			expectedError: true,
+		},
+		"Unknown field": {
+			data: `{"rules": [{"rulePriority": 1, "selection": {"tagStatus": "any", "countType": "sinceImagePulled",
+				"countUnit": "days", "countNumber": 1, "storageClass": "archive"}, "action": {"type": "expire"}}]}`,
+			expectedError: true,
+		},
+		"Duplicated priority": {
+			data: `{"rules": [
+				{"rulePriority": 1, "selection": {"tagStatus": "untagged", "countType": "imageCountMoreThan", "countNumber": 1},
+					"action": {"type": "expire"}},
+				{"rulePriority": 1, "selection": {"tagStatus": "any", "countType": "imageCountMoreThan", "countNumber": 1},
+					"action": {"type": "expire"}}
+			]}`,
+			expectedError: true,
+		},
+		"Any rule without the highest priority": {
+			data: `{"rules": [
+				{"rulePriority": 1, "selection": {"tagStatus": "any", "countType": "imageCountMoreThan", "countNumber": 1},
+					"action": {"type": "expire"}},
+				{"rulePriority": 2, "selection": {"tagStatus": "untagged", "countType": "imageCountMoreThan", "countNumber": 1},
+					"action": {"type": "expire"}}
+			]}`,
+			expectedError: true,
+		},
+		"Tagged without prefixes": {
+			data: `{"rules": [{"rulePriority": 1, "selection": {"tagStatus": "tagged", "countType": "imageCountMoreThan",
+				"countNumber": 1}, "action": {"type": "expire"}}]}`,
+			expectedError: true,
+		},
+		"Untagged with prefixes": {
+			data: `{"rules": [{"rulePriority": 1, "selection": {"tagStatus": "untagged", "tagPrefixList": ["v"],
+				"countType": "imageCountMoreThan", "countNumber": 1}, "action": {"type": "expire"}}]}`,
+			expectedError: true,
+		},
+		"Unsupported count type": {
+			data: `{"rules": [{"rulePriority": 1, "selection": {"tagStatus": "untagged", "countType": "sinceImagePulled",
+				"countUnit": "days", "countNumber": 1}, "action": {"type": "expire"}}]}`,
+			expectedError: true,
+		},
+		"Missing count unit": {
+			data: `{"rules": [{"rulePriority": 1, "selection": {"tagStatus": "untagged", "countType": "sinceImagePushed",
+				"countNumber": 1}, "action": {"type": "expire"}}]}`,
+			expectedError: true,
+		},
+		"Zero count number": {
+			data: `{"rules": [{"rulePriority": 1, "selection": {"tagStatus": "untagged", "countType": "imageCountMoreThan",
+				"countNumber": 0}, "action": {"type": "expire"}}]}`,
+			expectedError: true,
+		},
+		"Unsupported action": {
+			data: `{"rules": [{"rulePriority": 1, "selection": {"tagStatus": "untagged", "countType": "imageCountMoreThan",
+				"countNumber": 1}, "action": {"type": "transition"}}]}`,
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := ParseLifecyclePolicy([]byte(testCase.data))
+			if testCase.expectedError {
+				if err == nil {
+					t.Errorf("Expected error, got %v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestGetLifecycleRetainedImageDigests(t *testing.T) {
+	t.Parallel()
+
+	image := func(digest string, imagePushedAt string, imageTags ...string) types.ImageDetail {
+		return types.ImageDetail{
+			ImageDigest:   aws.String(digest),
+			ImageTags:     imageTags,
+			ImagePushedAt: aws.Time(testTimeParse(t, imagePushedAt)),
+		}
+	}
+
+	tests := map[string]struct {
+		lifecyclePolicy  string
+		images           []types.ImageDetail
+		dependentDigests map[string]struct{}
+		expected         map[string]struct{}
+	}{
+		"Image count more than": {
+			lifecyclePolicy: `{"rules": [{"rulePriority": 1, "selection": {"tagStatus": "any",
+				"countType": "imageCountMoreThan", "countNumber": 2}, "action": {"type": "expire"}}]}`,
+			images: []types.ImageDetail{
+				image("digest1", "2022-08-01T00:00:00Z", "v1"),
+				image("digest3", "2022-08-03T00:00:00Z", "v3"),
+				image("digest2", "2022-08-02T00:00:00Z"),
+			},
+			expected: map[string]struct{}{
+				"digest2": {},
+				"digest3": {},
+			},
+		},
+		"Since image pushed": {
+			lifecyclePolicy: `{"rules": [{"rulePriority": 1, "selection": {"tagStatus": "untagged",
+				"countType": "sinceImagePushed", "countUnit": "days", "countNumber": 10}, "action": {"type": "expire"}}]}`,
+			images: []types.ImageDetail{
+				image("digest1", "2022-08-01T00:00:00Z"),
+				image("digest2", "2022-08-30T00:00:00Z"),
+				image("digest3", "2022-08-01T00:00:00Z", "v3"),
+			},
+			expected: map[string]struct{}{
+				"digest2": {},
+				"digest3": {},
+			},
+		},
+		"Image selected by a rule cannot be expired by a rule with lower priority": {
+			lifecyclePolicy: `{"rules": [
+				{"rulePriority": 1, "selection": {"tagStatus": "tagged", "tagPrefixList": ["prod"],
+					"countType": "imageCountMoreThan", "countNumber": 100}, "action": {"type": "expire"}},
+				{"rulePriority": 2, "selection": {"tagStatus": "any",
+					"countType": "imageCountMoreThan", "countNumber": 1}, "action": {"type": "expire"}}
+			]}`,
+			images: []types.ImageDetail{
+				image("digest1", "2022-08-01T00:00:00Z", "prod-1"),
+				image("digest2", "2022-08-02T00:00:00Z", "prod-2", "dev-2"),
+				image("digest3", "2022-08-03T00:00:00Z", "dev-3"),
+				image("digest4", "2022-08-04T00:00:00Z", "dev-4"),
+			},
+			expected: map[string]struct{}{
+				"digest1": {},
+				"digest2": {},
+				"digest4": {},
+			},
+		},
+		"All tag prefixes have to match": {
+			lifecyclePolicy: `{"rules": [{"rulePriority": 1, "selection": {"tagStatus": "tagged",
+				"tagPrefixList": ["prod", "v"], "countType": "sinceImagePushed", "countUnit": "days", "countNumber": 1},
+				"action": {"type": "expire"}}]}`,
+			images: []types.ImageDetail{
+				image("digest1", "2022-08-01T00:00:00Z", "prod", "v1"),
+				image("digest2", "2022-08-01T00:00:00Z", "prod"),
+			},
+			expected: map[string]struct{}{
+				"digest2": {},
+			},
+		},
+		"Tag patterns": {
+			lifecyclePolicy: `{"rules": [{"rulePriority": 1, "selection": {"tagStatus": "tagged",
+				"tagPatternList": ["*-rc*"], "countType": "sinceImagePushed", "countUnit": "days", "countNumber": 1},
+				"action": {"type": "expire"}}]}`,
+			images: []types.ImageDetail{
+				image("digest1", "2022-08-01T00:00:00Z", "v1-rc1"),
+				image("digest2", "2022-08-01T00:00:00Z", "v1"),
+				image("digest3", "2022-08-01T00:00:00Z", "v1.rc1"),
+			},
+			expected: map[string]struct{}{
+				"digest2": {},
+				"digest3": {},
+			},
+		},
+		"Dependents are not evaluated": {
+			lifecyclePolicy: `{"rules": [{"rulePriority": 1, "selection": {"tagStatus": "any",
+				"countType": "imageCountMoreThan", "countNumber": 1}, "action": {"type": "expire"}}]}`,
+			images: []types.ImageDetail{
+				image("digest1", "2022-08-01T00:00:00Z", "v1"),
+				image("digest2", "2022-08-02T00:00:00Z"),
+			},
+			dependentDigests: map[string]struct{}{
+				"digest2": {},
+			},
+			expected: map[string]struct{}{
+				"digest1": {},
+			},
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lifecyclePolicy, err := ParseLifecyclePolicy([]byte(testCase.lifecyclePolicy))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := getLifecycleRetainedImageDigests(
+				logger,
+				types.Repository{RepositoryUri: aws.String("repo1uri")},
+				testCase.images,
+				testCase.dependentDigests,
+				lifecyclePolicy,
+				testTimeParse(t, "2022-08-31T00:00:01Z"),
+			)
+
+			diff := cmp.Diff(testCase.expected, result)
+			if diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -1,16 +1,28 @@
 package cleaner
 
 import (
+	"bytes"
 	"github.com/hashicorp/go-hclog"
+	"io"
 	"os"
+	"sync"
 )
 
 const logLevelEnvVar = "BOX_LOG"
 const defaultLogLevel = "INFO"
+const loggerName = "aws-ecr-cleaner"
+
+var logLevel = hclog.LevelFromString(getLogLevel())
+
+// logOutput is shared by the logger and buffered logs of repositories, writes to it are guarded by logOutputLock
+var logOutput io.Writer = os.Stderr
+var logOutputLock sync.Mutex
 
 var logger = hclog.New(&hclog.LoggerOptions{
-	Name:  "aws-ecr-cleaner",
-	Level: hclog.LevelFromString(getLogLevel()),
+	Name:   loggerName,
+	Level:  logLevel,
+	Output: logOutput,
+	Mutex:  &logOutputLock,
 })
 
 func getLogLevel() string {
@@ -20,3 +32,51 @@ func getLogLevel() string {
 	}
 	return value
 }
+
+// newBufferedLogger returns a logger with the same settings as logger, but writing to the buffered logs.
+func newBufferedLogger(logs *bufferedLogs) hclog.Logger {
+	return hclog.New(&hclog.LoggerOptions{
+		Name:   loggerName,
+		Level:  logLevel,
+		Output: logs,
+	})
+}
+
+// bufferedLogs keeps log lines until they are flushed, lines written after the flush go to the output directly. Writes
+// to the output are guarded by logOutputLock, so they are not interleaved with other log lines.
+type bufferedLogs struct {
+	lock   sync.Mutex
+	buffer bytes.Buffer
+	// output is nil until the logs are flushed
+	output io.Writer
+}
+
+func (b *bufferedLogs) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.output == nil {
+		return b.buffer.Write(p)
+	}
+
+	logOutputLock.Lock()
+	defer logOutputLock.Unlock()
+
+	return b.output.Write(p)
+}
+
+// flush writes the buffered lines to output and switches to writing to it directly, flushing again does nothing.
+func (b *bufferedLogs) flush(output io.Writer) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.output != nil {
+		return
+	}
+
+	logOutputLock.Lock()
+	defer logOutputLock.Unlock()
+
+	_, _ = b.buffer.WriteTo(output)
+	b.output = output
+}
@@ -0,0 +1,202 @@
+package cleaner
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	boxaws "github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/aws"
+	gerrors "github.com/pkg/errors"
+	"io"
+	"os"
+	"path"
+	"sigs.k8s.io/yaml"
+	"strings"
+)
+
+const (
+	policyS3LocationPrefix  = "s3://"
+	policySsmLocationPrefix = "ssm:"
+)
+
+// Policy is a retention policy document, it contains named tag rule sets and repository policies.
+type Policy struct {
+	TagRuleSets map[string][]TagRule `json:"tagRuleSets,omitempty"`
+	// Repositories are checked in order, the first repository policy with a matching selector is used
+	Repositories []RepositoryPolicy `json:"repositories,omitempty"`
+}
+
+// RepositoryPolicy attaches retention settings to repositories matching the selector. Settings that are not set are
+// taken from repository tags and then from the defaults.
+type RepositoryPolicy struct {
+	Selector RepositorySelector `json:"selector"`
+	// Enabled enables or disables cleaning of matching repositories, BoxCleanerEnabled=false repository tag always
+	// disables it
+	Enabled   *bool    `json:"enabled,omitempty"`
+	KeepDays  *int     `json:"keepDays,omitempty"`
+	KeepCount *int     `json:"keepCount,omitempty"`
+	AgeBasis  AgeBasis `json:"ageBasis,omitempty"`
+	// UntaggedKeepDays is the minimal age of removed untagged images (0 removes them immediately)
+	UntaggedKeepDays *int `json:"untaggedKeepDays,omitempty"`
+	// TagRuleSet selects a named tag rule set, it cannot be used together with TagRules
+	TagRuleSet string    `json:"tagRuleSet,omitempty"`
+	TagRules   []TagRule `json:"tagRules,omitempty"`
+	// LifecyclePolicy replaces all other rules, it cannot be used together with EcrLifecyclePolicy
+	LifecyclePolicy *LifecyclePolicy `json:"lifecyclePolicy,omitempty"`
+	// EcrLifecyclePolicy enables evaluation of the lifecycle policy of the ECR repository instead of the other rules
+	EcrLifecyclePolicy *bool `json:"ecrLifecyclePolicy,omitempty"`
+}
+
+// RepositorySelector matches repositories by all of the criteria that are set, an empty selector matches all
+// repositories.
+type RepositorySelector struct {
+	NamePrefix string `json:"namePrefix,omitempty"`
+	// NameGlob has to match the whole repository name, * does not match /
+	NameGlob string            `json:"nameGlob,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+}
+
+// LoadPolicy reads and validates a policy document (YAML or JSON) from a local file, an S3 object
+// (s3://bucket/key) or an SSM parameter (ssm:name).
+func LoadPolicy(ctx context.Context, awsProvider *boxaws.Provider, location string) (Policy, error) {
+	data, err := readPolicy(ctx, awsProvider, location)
+	if err != nil {
+		return Policy{}, gerrors.Wrapf(err, "cannot read policy %v", location)
+	}
+
+	policy, err := ParsePolicy(data)
+	if err != nil {
+		return Policy{}, gerrors.Wrapf(err, "invalid policy %v", location)
+	}
+	return policy, nil
+}
+
+func readPolicy(ctx context.Context, awsProvider *boxaws.Provider, location string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(location, policyS3LocationPrefix):
+		bucket, key, _ := strings.Cut(strings.TrimPrefix(location, policyS3LocationPrefix), "/")
+		if bucket == "" || key == "" {
+			return nil, gerrors.Errorf("invalid S3 location, expected s3://bucket/key")
+		}
+
+		getObjectOutput, err := awsProvider.S3Client.GetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return nil, gerrors.Wrapf(err, "cannot get S3 object")
+		}
+		defer getObjectOutput.Body.Close()
+
+		return io.ReadAll(getObjectOutput.Body)
+
+	case strings.HasPrefix(location, policySsmLocationPrefix):
+		getParameterOutput, err := awsProvider.SsmClient.GetParameter(ctx, &ssm.GetParameterInput{
+			Name:           aws.String(strings.TrimPrefix(location, policySsmLocationPrefix)),
+			WithDecryption: aws.Bool(true),
+		})
+		if err != nil {
+			return nil, gerrors.Wrapf(err, "cannot get SSM parameter")
+		}
+
+		return []byte(aws.ToString(getParameterOutput.Parameter.Value)), nil
+
+	default:
+		return os.ReadFile(location)
+	}
+}
+
+// ParsePolicy parses and validates a policy document (YAML or JSON).
+func ParsePolicy(data []byte) (Policy, error) {
+	var policy Policy
+	err := yaml.UnmarshalStrict(data, &policy)
+	if err != nil {
+		return Policy{}, gerrors.Wrapf(err, "cannot parse policy")
+	}
+
+	for name, tagRules := range policy.TagRuleSets {
+		err := compileTagRules(tagRules)
+		if err != nil {
+			return Policy{}, gerrors.Wrapf(err, "invalid tag rule set %v", name)
+		}
+	}
+
+	for i := range policy.Repositories {
+		err := policy.Repositories[i].validate(policy.TagRuleSets)
+		if err != nil {
+			return Policy{}, gerrors.Wrapf(err, "invalid repository policy %v", i+1)
+		}
+	}
+
+	return policy, nil
+}
+
+func compileTagRules(tagRules []TagRule) error {
+	for i := range tagRules {
+		err := tagRules[i].compile()
+		if err != nil {
+			return gerrors.Wrapf(err, "invalid rule %v", i+1)
+		}
+	}
+	return nil
+}
+
+func (r *RepositoryPolicy) validate(tagRuleSets map[string][]TagRule) error {
+	if r.Selector.NameGlob != "" {
+		if _, err := path.Match(r.Selector.NameGlob, ""); err != nil {
+			return gerrors.Wrapf(err, "invalid nameGlob %v", r.Selector.NameGlob)
+		}
+	}
+	if (r.KeepDays != nil && *r.KeepDays < 0) || (r.KeepCount != nil && *r.KeepCount < 0) ||
+		(r.UntaggedKeepDays != nil && *r.UntaggedKeepDays < 0) {
+		return gerrors.New("keepDays, keepCount and untaggedKeepDays cannot be negative")
+	}
+	if r.AgeBasis != "" && !r.AgeBasis.IsValid() {
+		return gerrors.Errorf("invalid ageBasis %v", r.AgeBasis)
+	}
+	if r.TagRuleSet != "" {
+		if len(r.TagRules) > 0 {
+			return gerrors.New("tagRuleSet cannot be set together with tagRules")
+		}
+		if _, ok := tagRuleSets[r.TagRuleSet]; !ok {
+			return gerrors.Errorf("tag rule set %v does not exist", r.TagRuleSet)
+		}
+	}
+	if r.LifecyclePolicy != nil {
+		if r.EcrLifecyclePolicy != nil {
+			return gerrors.New("lifecyclePolicy cannot be set together with ecrLifecyclePolicy")
+		}
+		err := r.LifecyclePolicy.compile()
+		if err != nil {
+			return gerrors.Wrapf(err, "invalid lifecyclePolicy")
+		}
+	}
+	return compileTagRules(r.TagRules)
+}
+
+// getRepositoryPolicy returns the first repository policy matching the repository or nil if there is none.
+func (p *Policy) getRepositoryPolicy(repositoryName string, repositoryTagsMap map[string]string) *RepositoryPolicy {
+	for i := range p.Repositories {
+		if p.Repositories[i].Selector.matches(repositoryName, repositoryTagsMap) {
+			return &p.Repositories[i]
+		}
+	}
+	return nil
+}
+
+func (s *RepositorySelector) matches(repositoryName string, repositoryTagsMap map[string]string) bool {
+	if !strings.HasPrefix(repositoryName, s.NamePrefix) {
+		return false
+	}
+	if s.NameGlob != "" {
+		if matched, _ := path.Match(s.NameGlob, repositoryName); !matched {
+			return false
+		}
+	}
+	for key, value := range s.Tags {
+		if repositoryTagValue, ok := repositoryTagsMap[key]; !ok || repositoryTagValue != value {
+			return false
+		}
+	}
+	return true
+}
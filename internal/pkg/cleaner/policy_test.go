@@ -0,0 +1,353 @@
+package cleaner
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	boxaws "github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/aws"
+	"github.com/golang/mock/gomock"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPolicy = `
+tagRuleSets:
+  releases:
+    - tagGlob: "v*.*.*"
+      neverDelete: true
+repositories:
+  - selector:
+      namePrefix: team-a/
+    keepDays: 14
+    tagRuleSet: releases
+`
+
+func TestParsePolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		data          string
+		expectedError bool
+	}{
+		"Valid YAML policy": {
+			data: testPolicy,
+		},
+		"Valid JSON policy": {
+			data: `{"tagRuleSets": {"default": [
+				{"tagGlob": "v*.*.*", "neverDelete": true},
+				{"tagRegex": "pr-\\d+", "keepDays": 3, "keepCount": 5},
+				{"untagged": true, "keepDays": 1}
+			]}}`,
+		},
+		"Repository policy with inline tag rules": {
+			data: `
+repositories:
+  - selector:
+      nameGlob: "team-b/*"
+      tags:
+        Team: b
+    enabled: true
+    keepCount: 10
+    ageBasis: pull
+    untaggedKeepDays: 0
+    tagRules:
+      - tagRegex: "(pr|sha)-.+"
+        keepDays: 3
+`,
+		},
+		"Empty policy": {
+			data: ``,
+		},
+		"Invalid YAML": {
+			data:          `tagRuleSets: [`,
+			expectedError: true,
+		},
+		"Unknown field": {
+			data:          `{"tagRuleSets": {"default": [{"tagGlob": "v*", "keepWeeks": 3}]}}`,
+			expectedError: true,
+		},
+		"Unknown repository policy field": {
+			data:          `{"repositories": [{"selector": {"namePrefix": "team-a/"}, "keepWeeks": 3}]}`,
+			expectedError: true,
+		},
+		"No tag rule pattern": {
+			data:          `{"tagRuleSets": {"default": [{"keepDays": 3}]}}`,
+			expectedError: true,
+		},
+		"Both tag rule patterns": {
+			data:          `{"tagRuleSets": {"default": [{"tagGlob": "v*", "tagRegex": "v.*", "keepDays": 3}]}}`,
+			expectedError: true,
+		},
+		"Untagged rule with a pattern": {
+			data:          `{"tagRuleSets": {"default": [{"tagGlob": "v*", "untagged": true, "keepDays": 3}]}}`,
+			expectedError: true,
+		},
+		"Invalid tag glob": {
+			data:          `{"tagRuleSets": {"default": [{"tagGlob": "v[", "keepDays": 3}]}}`,
+			expectedError: true,
+		},
+		"Invalid tag regex": {
+			data:          `{"tagRuleSets": {"default": [{"tagRegex": "v(", "keepDays": 3}]}}`,
+			expectedError: true,
+		},
+		"Negative tag rule keep days": {
+			data:          `{"tagRuleSets": {"default": [{"tagGlob": "v*", "keepDays": -1}]}}`,
+			expectedError: true,
+		},
+		"Never delete with keep count": {
+			data:          `{"tagRuleSets": {"default": [{"tagGlob": "v*", "neverDelete": true, "keepCount": 3}]}}`,
+			expectedError: true,
+		},
+		"Invalid repository name glob": {
+			data:          `{"repositories": [{"selector": {"nameGlob": "team-a/["}}]}`,
+			expectedError: true,
+		},
+		"Negative repository keep count": {
+			data:          `{"repositories": [{"selector": {}, "keepCount": -1}]}`,
+			expectedError: true,
+		},
+		"Negative repository untagged keep days": {
+			data:          `{"repositories": [{"selector": {}, "untaggedKeepDays": -1}]}`,
+			expectedError: true,
+		},
+		"Invalid age basis": {
+			data:          `{"repositories": [{"selector": {}, "ageBasis": "access"}]}`,
+			expectedError: true,
+		},
+		"Repository policy with lifecycle policy": {
+			data: `
+repositories:
+  - selector:
+      namePrefix: team-c/
+    lifecyclePolicy:
+      rules:
+        - rulePriority: 1
+          selection:
+            tagStatus: any
+            countType: imageCountMoreThan
+            countNumber: 10
+          action:
+            type: expire
+  - selector:
+      namePrefix: team-d/
+    ecrLifecyclePolicy: true
+`,
+		},
+		"Invalid lifecycle policy": {
+			data:          `{"repositories": [{"selector": {}, "lifecyclePolicy": {"rules": []}}]}`,
+			expectedError: true,
+		},
+		"Lifecycle policy with ECR lifecycle policy": {
+			data: `{"repositories": [{"selector": {}, "ecrLifecyclePolicy": true, "lifecyclePolicy": {"rules": [
+				{"rulePriority": 1, "selection": {"tagStatus": "any", "countType": "imageCountMoreThan", "countNumber": 1},
+					"action": {"type": "expire"}}]}}]}`,
+			expectedError: true,
+		},
+		"Unknown tag rule set": {
+			data:          `{"repositories": [{"selector": {}, "tagRuleSet": "releases"}]}`,
+			expectedError: true,
+		},
+		"Tag rule set with tag rules": {
+			data: `{"tagRuleSets": {"releases": []}, "repositories": [{"selector": {}, "tagRuleSet": "releases",
+				"tagRules": [{"tagGlob": "v*", "neverDelete": true}]}]}`,
+			expectedError: true,
+		},
+		"Invalid repository tag rule": {
+			data:          `{"repositories": [{"selector": {}, "tagRules": [{"keepDays": 3}]}]}`,
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := ParsePolicy([]byte(testCase.data))
+			if testCase.expectedError {
+				if err == nil {
+					t.Errorf("Expected error, got %v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	t.Parallel()
+
+	policyFile := filepath.Join(t.TempDir(), "policy.yaml")
+	err := os.WriteFile(policyFile, []byte(testPolicy), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		location      string
+		mock          func(mockAwsProvider *boxaws.MockProvider)
+		expectedError bool
+	}{
+		"Local file": {
+			location: policyFile,
+			mock:     func(mockAwsProvider *boxaws.MockProvider) {},
+		},
+		"Missing local file": {
+			location:      policyFile + ".missing",
+			mock:          func(mockAwsProvider *boxaws.MockProvider) {},
+			expectedError: true,
+		},
+		"S3 object": {
+			location: "s3://bucket1/policies/policy.yaml",
+			mock: func(mockAwsProvider *boxaws.MockProvider) {
+				mockAwsProvider.MockS3Client.EXPECT().GetObject(gomock.Any(), &s3.GetObjectInput{
+					Bucket: aws.String("bucket1"),
+					Key:    aws.String("policies/policy.yaml"),
+				}).Return(&s3.GetObjectOutput{
+					Body: io.NopCloser(strings.NewReader(testPolicy)),
+				}, nil)
+			},
+		},
+		"Invalid S3 location": {
+			location:      "s3://bucket1",
+			mock:          func(mockAwsProvider *boxaws.MockProvider) {},
+			expectedError: true,
+		},
+		"SSM parameter": {
+			location: "ssm:/aws-ecr-cleaner/policy",
+			mock: func(mockAwsProvider *boxaws.MockProvider) {
+				mockAwsProvider.MockSsmClient.EXPECT().GetParameter(gomock.Any(), &ssm.GetParameterInput{
+					Name:           aws.String("/aws-ecr-cleaner/policy"),
+					WithDecryption: aws.Bool(true),
+				}).Return(&ssm.GetParameterOutput{
+					Parameter: &ssmtypes.Parameter{
+						Value: aws.String(testPolicy),
+					},
+				}, nil)
+			},
+		},
+		"Invalid SSM parameter value": {
+			location: "ssm:/aws-ecr-cleaner/policy",
+			mock: func(mockAwsProvider *boxaws.MockProvider) {
+				mockAwsProvider.MockSsmClient.EXPECT().GetParameter(gomock.Any(), gomock.Any()).Return(&ssm.GetParameterOutput{
+					Parameter: &ssmtypes.Parameter{
+						Value: aws.String("repositories: ["),
+					},
+				}, nil)
+			},
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockAwsProvider := boxaws.NewMockProvider(ctrl)
+			testCase.mock(mockAwsProvider)
+
+			result, err := LoadPolicy(context.TODO(), mockAwsProvider.Provider, testCase.location)
+			if testCase.expectedError {
+				if err == nil {
+					t.Errorf("Expected error, got %v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(result.Repositories) != 1 || *result.Repositories[0].KeepDays != 14 {
+				t.Errorf("Unexpected policy %v", result)
+			}
+		})
+	}
+}
+
+func TestGetRepositoryPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := testParsePolicy(t, `
+repositories:
+  - selector:
+      namePrefix: team-a/
+      tags:
+        Environment: prod
+    keepDays: 1
+  - selector:
+      namePrefix: team-a/
+    keepDays: 2
+  - selector:
+      nameGlob: "*/base-*"
+    keepDays: 3
+`)
+
+	tests := map[string]struct {
+		repositoryName    string
+		repositoryTagsMap map[string]string
+		expectedKeepDays  int
+		expectedNoPolicy  bool
+	}{
+		"Prefix and tags": {
+			repositoryName: "team-a/app1",
+			repositoryTagsMap: map[string]string{
+				"Environment": "prod",
+			},
+			expectedKeepDays: 1,
+		},
+		"First matching policy is used": {
+			repositoryName: "team-a/base-images",
+			repositoryTagsMap: map[string]string{
+				"Environment": "dev",
+			},
+			expectedKeepDays: 2,
+		},
+		"Glob": {
+			repositoryName:    "team-b/base-images",
+			repositoryTagsMap: map[string]string{},
+			expectedKeepDays:  3,
+		},
+		"Glob does not match across slashes": {
+			repositoryName:    "team-b/images/base-images",
+			repositoryTagsMap: map[string]string{},
+			expectedNoPolicy:  true,
+		},
+		"No matching policy": {
+			repositoryName:    "team-c/app1",
+			repositoryTagsMap: map[string]string{},
+			expectedNoPolicy:  true,
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := policy.getRepositoryPolicy(testCase.repositoryName, testCase.repositoryTagsMap)
+			if testCase.expectedNoPolicy {
+				if result != nil {
+					t.Errorf("Expected no policy, got %v", result)
+				}
+				return
+			}
+
+			if result == nil || *result.KeepDays != testCase.expectedKeepDays {
+				t.Errorf("Result %v different than expected keep days %v", result, testCase.expectedKeepDays)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package cleaner
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/hashicorp/go-hclog"
+	gerrors "github.com/pkg/errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultRepositoryConcurrency is the default number of repositories processed at the same time.
+const DefaultRepositoryConcurrency = 4
+
+// logFlushDeadlineMargin is the time before the deadline of the context at which logs of all repositories are flushed,
+// so the lines of images already removed are not lost when the run is stopped (e.g. by the Lambda timeout).
+const logFlushDeadlineMargin = 10 * time.Second
+
+type processRepositoryFunc func(ctx context.Context, logger hclog.Logger, repository types.Repository) (Result, error)
+
+// repositoryOutcome is the outcome of processing a single repository.
+type repositoryOutcome struct {
+	result Result
+	err    error
+}
+
+// processRepositories processes repositories by at most concurrency workers. Every repository logs to its own buffer and
+// the buffers are written to logOutput in the order of repositories, so the logs and the result do not depend on the
+// concurrency. When the context is done or its deadline is closer than logFlushDeadlineMargin, the buffers of all
+// remaining repositories (including the ones being processed) are flushed and their next lines are written directly.
+// After the first error (in the order of repositories) no more repositories are started and the error is returned
+// together with results of the repositories processed until then.
+func processRepositories(
+	ctx context.Context,
+	repositories []types.Repository,
+	concurrency int,
+	logOutput io.Writer,
+	processRepository processRepositoryFunc,
+) (Result, error) {
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var flushDeadline <-chan time.Time
+	if deadline, ok := ctx.Deadline(); ok {
+		flushTimer := time.NewTimer(time.Until(deadline) - logFlushDeadlineMargin)
+		defer flushTimer.Stop()
+		flushDeadline = flushTimer.C
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	logs := make([]*bufferedLogs, len(repositories))
+	for i := range logs {
+		logs[i] = &bufferedLogs{}
+	}
+
+	// every repository gets exactly one outcome, either from a worker or, if it has not been started, a context error
+	outcomes := make([]chan *repositoryOutcome, len(repositories))
+	for i := range outcomes {
+		outcomes[i] = make(chan *repositoryOutcome, 1)
+	}
+
+	repositoryIndexes := make(chan int)
+	go func() {
+		defer close(repositoryIndexes)
+		for i := range repositories {
+			select {
+			case repositoryIndexes <- i:
+			case <-ctx.Done():
+				outcomes[i] <- &repositoryOutcome{err: ctx.Err()}
+			}
+		}
+	}()
+
+	var workers sync.WaitGroup
+	for worker := 0; worker < concurrency && worker < len(repositories); worker++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for i := range repositoryIndexes {
+				outcome := &repositoryOutcome{}
+				outcome.result, outcome.err = processRepository(ctx, newBufferedLogger(logs[i]), repositories[i])
+				outcomes[i] <- outcome
+			}
+		}()
+	}
+
+	flushSignal := ctx.Done()
+	var result Result
+	var err error
+	for i, repository := range repositories {
+		var outcome *repositoryOutcome
+		for outcome == nil {
+			select {
+			case outcome = <-outcomes[i]:
+			case <-flushSignal:
+				flushAllLogs(logs[i:], logOutput)
+				flushSignal, flushDeadline = nil, nil
+			case <-flushDeadline:
+				flushAllLogs(logs[i:], logOutput)
+				flushSignal, flushDeadline = nil, nil
+			}
+		}
+		logs[i].flush(logOutput)
+		result.add(outcome.result)
+
+		if outcome.err != nil && err == nil {
+			cancel()
+			err = gerrors.Wrapf(outcome.err, "error processig %v repository", *repository.RepositoryName)
+		}
+	}
+	workers.Wait()
+
+	return result, err
+}
+
+func flushAllLogs(logs []*bufferedLogs, logOutput io.Writer) {
+	for _, repositoryLogs := range logs {
+		repositoryLogs.flush(logOutput)
+	}
+}
+
+// runConcurrently runs tasks at the same time, at most limit of them at once. After the first error the context of the
+// running tasks is cancelled, tasks not started yet are skipped and the error is returned.
+func runConcurrently(ctx context.Context, limit int, tasks []func(ctx context.Context) error) error {
+	if limit < 1 {
+		limit = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var errLock sync.Mutex
+	var err error
+
+	slots := make(chan struct{}, limit)
+	var workers sync.WaitGroup
+	for _, task := range tasks {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		workers.Add(1)
+		go func(task func(ctx context.Context) error) {
+			defer workers.Done()
+			defer func() { <-slots }()
+
+			taskErr := task(ctx)
+			if taskErr != nil {
+				errLock.Lock()
+				if err == nil {
+					err = taskErr
+					cancel()
+				}
+				errLock.Unlock()
+			}
+		}(task)
+	}
+	workers.Wait()
+
+	if err != nil {
+		return err
+	}
+	// the parent context has been cancelled before all tasks were started
+	return ctx.Err()
+}
@@ -0,0 +1,309 @@
+package cleaner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/hashicorp/go-hclog"
+	gerrors "github.com/pkg/errors"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProcessRepositories(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		repositoryCount    int
+		concurrency        int
+		failedRepository   string
+		expectedLogs       []string
+		expectedRemovedIds int
+		expectedError      bool
+	}{
+		"Sequential": {
+			repositoryCount:    3,
+			concurrency:        1,
+			expectedLogs:       []string{"repo0", "repo1", "repo2"},
+			expectedRemovedIds: 3,
+		},
+		"Concurrent": {
+			repositoryCount:    8,
+			concurrency:        3,
+			expectedLogs:       []string{"repo0", "repo1", "repo2", "repo3", "repo4", "repo5", "repo6", "repo7"},
+			expectedRemovedIds: 8,
+		},
+		"Concurrency higher than number of repositories": {
+			repositoryCount:    2,
+			concurrency:        10,
+			expectedLogs:       []string{"repo0", "repo1"},
+			expectedRemovedIds: 2,
+		},
+		"Concurrency lower than 1": {
+			repositoryCount:    2,
+			concurrency:        0,
+			expectedLogs:       []string{"repo0", "repo1"},
+			expectedRemovedIds: 2,
+		},
+		"Error stops processing": {
+			repositoryCount:  8,
+			concurrency:      1,
+			failedRepository: "repo2",
+			// the repository started before the error was noticed may be logged too
+			expectedLogs:  []string{"repo0", "repo1", "repo2"},
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			repositories := make([]types.Repository, testCase.repositoryCount)
+			for i := range repositories {
+				repositories[i] = types.Repository{
+					RepositoryName: aws.String(fmt.Sprintf("repo%v", i)),
+				}
+			}
+
+			var activeWorkers int32
+			var maxActiveWorkers int32
+			var logOutput bytes.Buffer
+			result, err := processRepositories(context.TODO(), repositories, testCase.concurrency, &logOutput,
+				func(ctx context.Context, logger hclog.Logger, repository types.Repository) (Result, error) {
+					active := atomic.AddInt32(&activeWorkers, 1)
+					defer atomic.AddInt32(&activeWorkers, -1)
+					for {
+						maxActive := atomic.LoadInt32(&maxActiveWorkers)
+						if active <= maxActive || atomic.CompareAndSwapInt32(&maxActiveWorkers, maxActive, active) {
+							break
+						}
+					}
+
+					// earlier repositories take longer, so they finish later when processed concurrently
+					var repositoryIndex int
+					_, err := fmt.Sscanf(*repository.RepositoryName, "repo%d", &repositoryIndex)
+					if err != nil {
+						return Result{}, err
+					}
+					time.Sleep(time.Duration(testCase.repositoryCount-repositoryIndex) * time.Millisecond)
+
+					logger.Info("Processed repository", "repository", *repository.RepositoryName)
+					if *repository.RepositoryName == testCase.failedRepository {
+						return Result{}, gerrors.New("test error")
+					}
+					if err := ctx.Err(); err != nil {
+						return Result{}, err
+					}
+					return Result{RemovedImageIds: 1}, nil
+				})
+
+			var logs []string
+			for _, match := range regexp.MustCompile(`repository=(\S+)`).FindAllStringSubmatch(logOutput.String(), -1) {
+				logs = append(logs, match[1])
+			}
+
+			if testCase.expectedError {
+				if err == nil {
+					t.Error("Expected error")
+				}
+				if len(logs) == testCase.repositoryCount || !strings.HasPrefix(strings.Join(logs, ","), strings.Join(testCase.expectedLogs, ",")) {
+					t.Errorf("Logs %v do not start with expected %v or all repositories were processed", logs, testCase.expectedLogs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if strings.Join(logs, ",") != strings.Join(testCase.expectedLogs, ",") {
+				t.Errorf("Logs %v different than expected %v", logs, testCase.expectedLogs)
+			}
+			if result.RemovedImageIds != testCase.expectedRemovedIds {
+				t.Errorf("Removed image ids %v different than expected %v", result.RemovedImageIds, testCase.expectedRemovedIds)
+			}
+
+			expectedMaxActiveWorkers := int32(testCase.concurrency)
+			if expectedMaxActiveWorkers < 1 {
+				expectedMaxActiveWorkers = 1
+			}
+			if maxActiveWorkers > expectedMaxActiveWorkers {
+				t.Errorf("Max active workers %v higher than concurrency %v", maxActiveWorkers, testCase.concurrency)
+			}
+		})
+	}
+}
+
+func TestProcessRepositoriesFlushesLogs(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		// cancelled means that the context is cancelled by the test instead of getting close to its deadline
+		cancelled bool
+	}{
+		"Context deadline close": {},
+		"Context cancelled": {
+			cancelled: true,
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			if !testCase.cancelled {
+				// the deadline is further than the margin, the logs are flushed when it gets closer
+				ctx, cancel = context.WithTimeout(context.TODO(), logFlushDeadlineMargin+100*time.Millisecond)
+			}
+			defer cancel()
+
+			repositories := []types.Repository{
+				{RepositoryName: aws.String("repo0")},
+				{RepositoryName: aws.String("repo1")},
+			}
+
+			var logOutput bytes.Buffer
+			logsWritten := func(repositoryName string) bool {
+				logOutputLock.Lock()
+				defer logOutputLock.Unlock()
+				return strings.Contains(logOutput.String(), "repository="+repositoryName)
+			}
+
+			repo1Processed := make(chan struct{})
+			var repo1LogsWrittenEarly bool
+			_, err := processRepositories(ctx, repositories, 2, &logOutput,
+				func(ctx context.Context, logger hclog.Logger, repository types.Repository) (Result, error) {
+					logger.Info("Found unused image, removing", "repository", *repository.RepositoryName)
+					if *repository.RepositoryName == "repo1" {
+						close(repo1Processed)
+						return Result{RemovedImageIds: 1}, nil
+					}
+
+					// the first repository is slow, logs of the second one should not wait for it
+					<-repo1Processed
+					if testCase.cancelled {
+						cancel()
+					}
+					for i := 0; i < 100 && !repo1LogsWrittenEarly; i++ {
+						repo1LogsWrittenEarly = logsWritten("repo1")
+						time.Sleep(10 * time.Millisecond)
+					}
+					logger.Info("Finished slow repository", "repository", *repository.RepositoryName)
+					return Result{RemovedImageIds: 1}, nil
+				})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !repo1LogsWrittenEarly {
+				t.Error("Logs of the finished repository not written before the slow repository finished")
+			}
+			if !logsWritten("repo0") {
+				t.Error("Logs of the slow repository not written")
+			}
+		})
+	}
+}
+
+func TestRunConcurrently(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		taskCount        int
+		limit            int
+		failedTask       int
+		expectedMaxTasks int32
+		expectedError    bool
+	}{
+		"No tasks": {
+			taskCount:  0,
+			limit:      2,
+			failedTask: -1,
+		},
+		"Limited concurrency": {
+			taskCount:        10,
+			limit:            3,
+			failedTask:       -1,
+			expectedMaxTasks: 3,
+		},
+		"Limit lower than 1": {
+			taskCount:        3,
+			limit:            0,
+			failedTask:       -1,
+			expectedMaxTasks: 1,
+		},
+		"Error stops remaining tasks": {
+			taskCount:        10,
+			limit:            1,
+			failedTask:       2,
+			expectedMaxTasks: 1,
+			expectedError:    true,
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var startedTasks int32
+			var activeTasks int32
+			var maxActiveTasks int32
+			tasks := make([]func(ctx context.Context) error, testCase.taskCount)
+			for i := range tasks {
+				// capture range variables
+				i := i
+				tasks[i] = func(ctx context.Context) error {
+					atomic.AddInt32(&startedTasks, 1)
+					active := atomic.AddInt32(&activeTasks, 1)
+					defer atomic.AddInt32(&activeTasks, -1)
+					for {
+						maxActive := atomic.LoadInt32(&maxActiveTasks)
+						if active <= maxActive || atomic.CompareAndSwapInt32(&maxActiveTasks, maxActive, active) {
+							break
+						}
+					}
+
+					time.Sleep(time.Millisecond)
+					if i == testCase.failedTask {
+						return gerrors.New("test error")
+					}
+					return nil
+				}
+			}
+
+			err := runConcurrently(context.TODO(), testCase.limit, tasks)
+
+			if testCase.expectedError {
+				if err == nil {
+					t.Error("Expected error")
+				}
+				if startedTasks != int32(testCase.failedTask+1) {
+					t.Errorf("Started tasks %v different than expected %v", startedTasks, testCase.failedTask+1)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if startedTasks != int32(testCase.taskCount) {
+				t.Errorf("Started tasks %v different than expected %v", startedTasks, testCase.taskCount)
+			}
+			if maxActiveTasks > testCase.expectedMaxTasks {
+				t.Errorf("Max active tasks %v higher than limit %v", maxActiveTasks, testCase.expectedMaxTasks)
+			}
+		})
+	}
+}
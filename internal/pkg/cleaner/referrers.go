@@ -0,0 +1,98 @@
+package cleaner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/hashicorp/go-hclog"
+	gerrors "github.com/pkg/errors"
+	"regexp"
+)
+
+const (
+	OciImageManifestMediaType     = "application/vnd.oci.image.manifest.v1+json"
+	OciImageConfigMediaType       = "application/vnd.oci.image.config.v1+json"
+	DockerContainerImageMediaType = "application/vnd.docker.container.image.v1+json"
+)
+
+// cosignTagRegexp matches tags of cosign signatures, attestations and SBOMs (and of the OCI referrers tag schema
+// fallback), e.g. sha256-<hex>.sig, they point to the subject image with the sha256:<hex> digest.
+var cosignTagRegexp = regexp.MustCompile(`^(sha256)-([a-f0-9]{64})(?:\.(?:sig|att|sbom))?$`)
+
+// referrerManifest is the part of an OCI manifest needed to find the subject of a referrer.
+type referrerManifest struct {
+	Subject *struct {
+		Digest string `json:"digest"`
+	} `json:"subject"`
+}
+
+// getImageReferrers returns digests of referrers (signatures, attestations, SBOMs) of images in the repository, by
+// the digest of the subject image. Referrers are found by cosign tags and by the OCI subject field of artifacts.
+// Referrers of subjects that are not in the repository are not returned.
+func (c *Cleaner) getImageReferrers(ctx context.Context, logger hclog.Logger, repository types.Repository, images []types.ImageDetail) (map[string][]string, error) {
+	imageDigests := make(map[string]struct{}, len(images))
+	for _, image := range images {
+		imageDigests[*image.ImageDigest] = struct{}{}
+	}
+
+	referrers := make(map[string][]string)
+	addReferrer := func(subjectDigest string, referrerDigest string) {
+		if _, ok := imageDigests[subjectDigest]; !ok || subjectDigest == referrerDigest {
+			return
+		}
+		for _, existingReferrerDigest := range referrers[subjectDigest] {
+			if existingReferrerDigest == referrerDigest {
+				return
+			}
+		}
+
+		logger.Debug("Found image referrer", "repository", *repository.RepositoryUri,
+			"subjectDigest", subjectDigest, "referrerDigest", referrerDigest)
+
+		referrers[subjectDigest] = append(referrers[subjectDigest], referrerDigest)
+	}
+
+	var artifactDigests []string
+	for _, image := range images {
+		for _, imageTag := range image.ImageTags {
+			if matches := cosignTagRegexp.FindStringSubmatch(imageTag); matches != nil {
+				addReferrer(fmt.Sprintf("%v:%v", matches[1], matches[2]), *image.ImageDigest)
+			}
+		}
+
+		if isArtifact(image) {
+			artifactDigests = append(artifactDigests, *image.ImageDigest)
+		}
+	}
+
+	artifacts, err := c.batchGetImages(ctx, repository, artifactDigests, []string{OciImageManifestMediaType})
+	if err != nil {
+		return nil, gerrors.Wrapf(err, "cannot get artifacts")
+	}
+
+	for _, artifact := range artifacts {
+		var manifest referrerManifest
+		err := json.Unmarshal([]byte(aws.ToString(artifact.ImageManifest)), &manifest)
+		if err != nil {
+			return nil, gerrors.Wrapf(err, "cannot parse artifact %v", *artifact.ImageId.ImageDigest)
+		}
+
+		if manifest.Subject != nil {
+			addReferrer(manifest.Subject.Digest, *artifact.ImageId.ImageDigest)
+		}
+	}
+
+	return referrers, nil
+}
+
+// isArtifact checks if the image is an OCI manifest of something else than a container image (only these can have
+// a subject).
+func isArtifact(image types.ImageDetail) bool {
+	if aws.ToString(image.ImageManifestMediaType) != OciImageManifestMediaType || image.ArtifactMediaType == nil {
+		return false
+	}
+	artifactMediaType := *image.ArtifactMediaType
+	return artifactMediaType != OciImageConfigMediaType && artifactMediaType != DockerContainerImageMediaType
+}
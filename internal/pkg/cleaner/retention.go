@@ -0,0 +1,165 @@
+package cleaner
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/hashicorp/go-hclog"
+	"sort"
+	"time"
+)
+
+// AgeBasis selects the time the age of images is measured from.
+type AgeBasis string
+
+const (
+	// AgeBasisPush measures the age from the push time
+	AgeBasisPush AgeBasis = "push"
+	// AgeBasisPull measures the age from the later of the push time and the last recorded pull time, images that have
+	// never been pulled (or were last pulled before ECR started recording pulls) fall back to the push time
+	AgeBasisPull AgeBasis = "pull"
+)
+
+// IsValid checks if the age basis is one of the known ones.
+func (a AgeBasis) IsValid() bool {
+	return a == AgeBasisPush || a == AgeBasisPull
+}
+
+// retention holds rules of a repository deciding which unused images are kept.
+type retention struct {
+	// keepDays is the minimal age of removed images not matched by any tag rule
+	keepDays int
+	// keepCount is the number of the most recently pushed images not matched by any tag rule that are always kept
+	keepCount int
+	// ageBasis is used for keepDays of all rules, keepCount always uses the push time
+	ageBasis AgeBasis
+	// lifecyclePolicy replaces all other rules if set
+	lifecyclePolicy *LifecyclePolicy
+	// tagRules replace keepDays and keepCount for images with matching tags, the first matching rule of every tag is used
+	tagRules []TagRule
+	// untaggedKeepDays replaces keepDays and keepCount for untagged images not matched by any of tagRules, nil means
+	// that untagged images are retained the same way as tagged ones
+	untaggedKeepDays *int
+}
+
+// getRetainedImageDigests returns digests of images kept by the retention rules regardless of being used. Dependents are
+// not checked (they are kept together with the images they depend on). If the repository has a lifecycle policy, only
+// the lifecycle policy is evaluated. Otherwise images are grouped by the tag rules matching their tags or by untagged
+// rules (images with any tag not matching a rule are in the default group too) and every group is checked separately, so
+// an image matched by multiple rules is kept if any of them keeps it.
+func getRetainedImageDigests(
+	logger hclog.Logger,
+	repository types.Repository,
+	images []types.ImageDetail,
+	dependentDigests map[string]struct{},
+	retention retention,
+	startTime time.Time,
+) map[string]struct{} {
+
+	if retention.lifecyclePolicy != nil {
+		return getLifecycleRetainedImageDigests(logger, repository, images, dependentDigests, retention.lifecyclePolicy, startTime)
+	}
+
+	defaultRule := TagRule{
+		KeepDays:  retention.keepDays,
+		KeepCount: retention.keepCount,
+	}
+
+	tagRules := retention.tagRules
+	if retention.untaggedKeepDays != nil {
+		// the full slice expression makes sure that the shared tag rules are copied, not modified
+		tagRules = append(tagRules[:len(tagRules):len(tagRules)], TagRule{
+			Untagged: true,
+			KeepDays: *retention.untaggedKeepDays,
+		})
+	}
+
+	// the last group is the default one
+	imageGroups := make([][]types.ImageDetail, len(tagRules)+1)
+	for _, image := range images {
+		if _, isDependent := dependentDigests[*image.ImageDigest]; isDependent {
+			continue
+		}
+
+		imageTags := image.ImageTags
+		if len(imageTags) == 0 {
+			// untagged images are matched by untagged rules only
+			imageTags = []string{""}
+		}
+
+		// a tag matching no rule puts the image into the default group, so the image is kept if any of its tags keeps it
+		matchedRules := make(map[int]struct{})
+		for _, imageTag := range imageTags {
+			matchedRule := len(tagRules)
+			for i := range tagRules {
+				if tagRules[i].matches(imageTag) {
+					matchedRule = i
+					break
+				}
+			}
+			matchedRules[matchedRule] = struct{}{}
+		}
+
+		for i := range matchedRules {
+			imageGroups[i] = append(imageGroups[i], image)
+		}
+	}
+
+	retainedImageDigests := make(map[string]struct{})
+	for i, imageGroup := range imageGroups {
+		rule := defaultRule
+		if i < len(tagRules) {
+			rule = tagRules[i]
+		}
+		retainImages(logger, repository, rule, retention.ageBasis, imageGroup, startTime, retainedImageDigests)
+	}
+	return retainedImageDigests
+}
+
+// retainImages adds digests of images kept by the rule to retainedImageDigests.
+func retainImages(
+	logger hclog.Logger,
+	repository types.Repository,
+	rule TagRule,
+	ageBasis AgeBasis,
+	images []types.ImageDetail,
+	startTime time.Time,
+	retainedImageDigests map[string]struct{},
+) {
+
+	sort.SliceStable(images, func(i, j int) bool {
+		return images[i].ImagePushedAt.After(*images[j].ImagePushedAt)
+	})
+
+	for i, image := range images {
+		if rule.NeverDelete {
+			logger.Debug("Found image protected by a tag rule", "repository", *repository.RepositoryUri,
+				"imageDigest", *image.ImageDigest, "imageTags", image.ImageTags)
+
+			retainedImageDigests[*image.ImageDigest] = struct{}{}
+			continue
+		}
+
+		if i < rule.KeepCount {
+			logger.Debug("Found one of the newest images", "repository", *repository.RepositoryUri,
+				"imageDigest", *image.ImageDigest, "keepCount", rule.KeepCount)
+
+			retainedImageDigests[*image.ImageDigest] = struct{}{}
+			continue
+		}
+
+		imageAgeDays := startTime.Sub(getImageAgeStartTime(image, ageBasis)).Hours() / 24
+		if imageAgeDays <= float64(rule.KeepDays) {
+			retainedImageDigests[*image.ImageDigest] = struct{}{}
+			continue
+		}
+
+		logger.Debug("Found old image", "repository", *repository.RepositoryUri,
+			"imageDigest", *image.ImageDigest, "imageAgeDays", imageAgeDays)
+	}
+}
+
+func getImageAgeStartTime(image types.ImageDetail, ageBasis AgeBasis) time.Time {
+	if ageBasis == AgeBasisPull && image.LastRecordedPullTime != nil && image.LastRecordedPullTime.After(*image.ImagePushedAt) {
+		return *image.LastRecordedPullTime
+	}
+	return *image.ImagePushedAt
+}
@@ -0,0 +1,69 @@
+package cleaner
+
+import (
+	gerrors "github.com/pkg/errors"
+	"path"
+	"regexp"
+)
+
+// DefaultTagRuleSet is the name of the tag rule set used by repositories without the BoxCleanerTagRules tag (and without
+// tag rules in their repository policy).
+const DefaultTagRuleSet = "default"
+
+// TagRule assigns its own retention to images with a tag matching TagGlob or TagRegex, or to untagged images if Untagged
+// is set (exactly one of them is set). Matched images are kept if they are younger than KeepDays or are among the
+// KeepCount most recently pushed images matched by the rule, or always if NeverDelete is set.
+type TagRule struct {
+	TagGlob     string `json:"tagGlob,omitempty"`
+	TagRegex    string `json:"tagRegex,omitempty"`
+	Untagged    bool   `json:"untagged,omitempty"`
+	KeepDays    int    `json:"keepDays,omitempty"`
+	KeepCount   int    `json:"keepCount,omitempty"`
+	NeverDelete bool   `json:"neverDelete,omitempty"`
+
+	tagRegexp *regexp.Regexp
+}
+
+func (r *TagRule) compile() error {
+	matchers := 0
+	for _, isSet := range []bool{r.TagGlob != "", r.TagRegex != "", r.Untagged} {
+		if isSet {
+			matchers++
+		}
+	}
+	if matchers != 1 {
+		return gerrors.New("exactly one of tagGlob, tagRegex and untagged has to be set")
+	}
+	if r.KeepDays < 0 || r.KeepCount < 0 {
+		return gerrors.New("keepDays and keepCount cannot be negative")
+	}
+	if r.NeverDelete && (r.KeepDays > 0 || r.KeepCount > 0) {
+		return gerrors.New("keepDays and keepCount cannot be set together with neverDelete")
+	}
+
+	if r.TagGlob != "" {
+		if _, err := path.Match(r.TagGlob, ""); err != nil {
+			return gerrors.Wrapf(err, "invalid tagGlob %v", r.TagGlob)
+		}
+	} else if r.TagRegex != "" {
+		// the whole tag has to match, the same as for globs
+		tagRegexp, err := regexp.Compile("^(?:" + r.TagRegex + ")$")
+		if err != nil {
+			return gerrors.Wrapf(err, "invalid tagRegex %v", r.TagRegex)
+		}
+		r.tagRegexp = tagRegexp
+	}
+	return nil
+}
+
+// matches checks if the rule matches the tag, an empty tag means an untagged image.
+func (r *TagRule) matches(tag string) bool {
+	if r.Untagged || tag == "" {
+		return r.Untagged && tag == ""
+	}
+	if r.tagRegexp != nil {
+		return r.tagRegexp.MatchString(tag)
+	}
+	matched, _ := path.Match(r.TagGlob, tag)
+	return matched
+}
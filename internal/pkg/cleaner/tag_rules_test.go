@@ -0,0 +1,71 @@
+package cleaner
+
+import (
+	"testing"
+)
+
+func TestTagRuleMatches(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		rule     TagRule
+		tag      string
+		expected bool
+	}{
+		"Glob matches": {
+			rule:     TagRule{TagGlob: "v*.*.*"},
+			tag:      "v1.2.3",
+			expected: true,
+		},
+		"Glob does not match": {
+			rule:     TagRule{TagGlob: "v*.*.*"},
+			tag:      "v1.2",
+			expected: false,
+		},
+		"Regex matches": {
+			rule:     TagRule{TagRegex: `pr-\d+`},
+			tag:      "pr-123",
+			expected: true,
+		},
+		"Regex has to match the whole tag": {
+			rule:     TagRule{TagRegex: `pr-\d+`},
+			tag:      "pr-123-fix",
+			expected: false,
+		},
+		"Untagged rule matches untagged image": {
+			rule:     TagRule{Untagged: true},
+			tag:      "",
+			expected: true,
+		},
+		"Untagged rule does not match tag": {
+			rule:     TagRule{Untagged: true},
+			tag:      "v1",
+			expected: false,
+		},
+		"Glob does not match untagged image": {
+			rule:     TagRule{TagGlob: "*"},
+			tag:      "",
+			expected: false,
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rule := testCase.rule
+			err := rule.compile()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := rule.matches(testCase.tag)
+
+			if result != testCase.expected {
+				t.Errorf("Result %v different than expected %v", result, testCase.expected)
+			}
+		})
+	}
+}
@@ -2,107 +2,415 @@ package cleaner
 
 import (
 	"context"
+	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/apprunner"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
 	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	boxaws "github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/aws"
+	boxkubernetes "github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/kubernetes"
+	"github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/reference"
 	gerrors "github.com/pkg/errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 const AppRunnerRegionsSsmParametersPath = "/aws/service/global-infrastructure/services/apprunner/regions"
 
+const LambdaLatestVersion = "$LATEST"
+
+// ecsClusterConcurrency is the number of ECS clusters searched for used images at the same time.
+const ecsClusterConcurrency = 4
+
 type usedImages struct {
-	awsProvider *boxaws.Provider
+	awsProvider        *boxaws.Provider
+	usageAwsProviders  []*boxaws.Provider
+	kubernetesClusters []boxkubernetes.Cluster
+	config             Config
+	// startTime is the start time of the run, used instead of the current time
+	startTime          time.Time
+	ecsTaskDefinitions *ecsTaskDefinitionCache
 }
 
-func (u *usedImages) getImages() (map[string]struct{}, error) {
-	imageSet := make(map[string]struct{})
+// usedImageSet is a set of used images safe for concurrent writers.
+type usedImageSet struct {
+	lock   sync.Mutex
+	images map[string]struct{}
+}
 
-	err := u.getEcsUsedImages(imageSet)
-	if err != nil {
-		return nil, gerrors.Wrapf(err, "error getting images used by ECS")
+func newUsedImageSet() *usedImageSet {
+	return &usedImageSet{
+		images: make(map[string]struct{}),
 	}
+}
 
-	err = u.getLambdaUsedImages(imageSet)
-	if err != nil {
-		return nil, gerrors.Wrapf(err, "error getting images used by Lambda")
+func (s *usedImageSet) add(image string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.images[image] = struct{}{}
+}
+
+// getImages returns images used by AWS services reachable through all providers (regions and accounts) and by
+// Kubernetes clusters, an image can only be removed if it is unused everywhere. Any error means that the set is
+// incomplete, so it must not be used for removing images.
+func (u *usedImages) getImages(ctx context.Context) (map[string]struct{}, error) {
+	imageSet := newUsedImageSet()
+
+	awsProviders := append([]*boxaws.Provider{u.awsProvider}, u.usageAwsProviders...)
+	for _, awsProvider := range awsProviders {
+		providerUsedImages := &usedImages{
+			awsProvider:        awsProvider,
+			config:             u.config,
+			startTime:          u.startTime,
+			ecsTaskDefinitions: newEcsTaskDefinitionCache(),
+		}
+		err := providerUsedImages.getAwsUsedImages(ctx, imageSet)
+		if err != nil {
+			if awsProvider.RoleArn != "" {
+				return nil, gerrors.Wrapf(err, "error getting images used in region %v with role %v",
+					awsProvider.Region, awsProvider.RoleArn)
+			}
+			return nil, gerrors.Wrapf(err, "error getting images used in region %v", awsProvider.Region)
+		}
 	}
 
-	appRunnerEnabled, err := u.checkAppRunnerEnabledInRegion()
+	err := u.getKubernetesUsedImages(ctx, imageSet)
 	if err != nil {
-		return nil, gerrors.Wrapf(err, "error checking if App Runner is enabled in region")
+		return nil, gerrors.Wrapf(err, "error getting images used by Kubernetes")
 	}
 
-	if appRunnerEnabled {
-		err = u.getAppRunnerUsedImages(imageSet)
-		if err != nil {
-			return nil, gerrors.Wrapf(err, "error getting images used by App Runner")
+	return normalizeImageSet(imageSet.images), nil
+}
+
+// normalizeImageSet converts all images to their normalized identifiers, so that equivalent references (e.g. with and
+// without the implicit latest tag) match each other.
+func normalizeImageSet(imageSet map[string]struct{}) map[string]struct{} {
+	normalizedImageSet := make(map[string]struct{}, len(imageSet))
+	for image := range imageSet {
+		for _, imageId := range reference.Normalize(image) {
+			normalizedImageSet[imageId] = struct{}{}
 		}
-	} else {
-		logger.Info("App Runner not available in this region", "region", u.awsProvider.Region)
 	}
+	return normalizedImageSet
+}
 
-	return imageSet, nil
+// getAwsUsedImages searches all enabled AWS services for used images at the same time, the first error stops the
+// search.
+func (u *usedImages) getAwsUsedImages(ctx context.Context, imageSet *usedImageSet) error {
+	sources := []func(ctx context.Context) error{
+		func(ctx context.Context) error {
+			err := u.getEcsUsedImages(ctx, imageSet)
+			if err != nil {
+				return gerrors.Wrapf(err, "error getting images used by ECS")
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			err := u.getLambdaUsedImages(ctx, imageSet)
+			if err != nil {
+				return gerrors.Wrapf(err, "error getting images used by Lambda")
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			appRunnerEnabled, err := u.checkAppRunnerEnabledInRegion(ctx)
+			if err != nil {
+				return gerrors.Wrapf(err, "error checking if App Runner is enabled in region")
+			}
+
+			if !appRunnerEnabled {
+				logger.Info("App Runner not available in this region", "region", u.awsProvider.Region)
+				return nil
+			}
+
+			err = u.getAppRunnerUsedImages(ctx, imageSet)
+			if err != nil {
+				return gerrors.Wrapf(err, "error getting images used by App Runner")
+			}
+			return nil
+		},
+	}
+
+	if u.config.ScheduledTasksEnabled {
+		sources = append(sources, func(ctx context.Context) error {
+			err := u.getScheduledTasksUsedImages(ctx, imageSet)
+			if err != nil {
+				return gerrors.Wrapf(err, "error getting images used by scheduled ECS tasks")
+			}
+			return nil
+		})
+	}
+
+	if u.config.BatchEnabled {
+		sources = append(sources, func(ctx context.Context) error {
+			err := u.getBatchUsedImages(ctx, imageSet)
+			if err != nil {
+				return gerrors.Wrapf(err, "error getting images used by Batch")
+			}
+			return nil
+		})
+	}
+
+	if u.config.SageMakerEnabled {
+		sources = append(sources, func(ctx context.Context) error {
+			err := u.getSageMakerUsedImages(ctx, imageSet)
+			if err != nil {
+				return gerrors.Wrapf(err, "error getting images used by SageMaker")
+			}
+			return nil
+		})
+	}
+
+	if u.config.CodeBuildEnabled {
+		sources = append(sources, func(ctx context.Context) error {
+			err := u.getCodeBuildUsedImages(ctx, imageSet)
+			if err != nil {
+				return gerrors.Wrapf(err, "error getting images used by CodeBuild")
+			}
+			return nil
+		})
+	}
+
+	return runConcurrently(ctx, len(sources), sources)
 }
 
-func (u *usedImages) getEcsUsedImages(imageSet map[string]struct{}) error {
+// getEcsUsedImages lists all ECS clusters first and then searches up to ecsClusterConcurrency clusters at the same time.
+func (u *usedImages) getEcsUsedImages(ctx context.Context, imageSet *usedImageSet) error {
 	ecsPaginators := u.awsProvider.EcsPaginators
-	ecsClient := u.awsProvider.EcsClient
 
+	var clusterArns []string
 	listClustersPaginator := ecsPaginators.NewListClustersPaginator(&ecs.ListClustersInput{})
 	for listClustersPaginator.HasMorePages() {
-		listClusterPage, err := listClustersPaginator.NextPage(context.TODO())
+		listClusterPage, err := listClustersPaginator.NextPage(ctx)
 		if err != nil {
 			return gerrors.Wrapf(err, "cannot get list ECS clusters page")
 		}
 
-		for _, clusterArn := range listClusterPage.ClusterArns {
+		clusterArns = append(clusterArns, listClusterPage.ClusterArns...)
+	}
 
-			listServicesPaginator := ecsPaginators.NewListServicesPaginator(&ecs.ListServicesInput{
-				Cluster: aws.String(clusterArn),
-			})
+	clusters := make([]func(ctx context.Context) error, 0, len(clusterArns))
+	for _, clusterArn := range clusterArns {
+		// capture range variable
+		clusterArn := clusterArn
+		clusters = append(clusters, func(ctx context.Context) error {
+			return u.getEcsClusterUsedImages(ctx, clusterArn, imageSet)
+		})
+	}
 
-			for listServicesPaginator.HasMorePages() {
-				listServicesPage, err := listServicesPaginator.NextPage(context.TODO())
-				if err != nil {
-					return gerrors.Wrapf(err, "cannot get list ECS services page")
-				}
+	return runConcurrently(ctx, ecsClusterConcurrency, clusters)
+}
 
-				if len(listServicesPage.ServiceArns) > 0 {
+func (u *usedImages) getEcsClusterUsedImages(ctx context.Context, clusterArn string, imageSet *usedImageSet) error {
+	ecsPaginators := u.awsProvider.EcsPaginators
+	ecsClient := u.awsProvider.EcsClient
+
+	listServicesPaginator := ecsPaginators.NewListServicesPaginator(&ecs.ListServicesInput{
+		Cluster: aws.String(clusterArn),
+	})
+
+	for listServicesPaginator.HasMorePages() {
+		listServicesPage, err := listServicesPaginator.NextPage(ctx)
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot get list ECS services page")
+		}
+
+		if len(listServicesPage.ServiceArns) > 0 {
+
+			describeServicesOutput, err :=
+				ecsClient.DescribeServices(ctx, &ecs.DescribeServicesInput{
+					Services: listServicesPage.ServiceArns,
+					Cluster:  aws.String(clusterArn),
+				})
+			if err != nil {
+				return gerrors.Wrapf(err, "cannot describe ECS services")
+			}
 
-					describeServicesOutput, err :=
-						ecsClient.DescribeServices(context.TODO(), &ecs.DescribeServicesInput{
-							Services: listServicesPage.ServiceArns,
-							Cluster:  aws.String(clusterArn),
-						})
+			for _, service := range describeServicesOutput.Services {
+
+				for _, taskDefinition := range getEcsServiceTaskDefinitions(service) {
+
+					images, err := u.getEcsTaskDefinitionImages(ctx, taskDefinition)
 					if err != nil {
-						return gerrors.Wrapf(err, "cannot describe ECS services")
+						return gerrors.Wrapf(err, "cannot get images of ECS service %v", *service.ServiceName)
 					}
 
-					for _, service := range describeServicesOutput.Services {
+					for _, image := range images {
+						logger.Debug("Found image used by ECS service",
+							"image", image, "ecsService", *service.ServiceName, "taskDefinition", taskDefinition)
 
-						describeTaskDefinitionOutput, err :=
-							ecsClient.DescribeTaskDefinition(context.TODO(), &ecs.DescribeTaskDefinitionInput{
-								TaskDefinition: service.TaskDefinition,
-							})
-						if err != nil {
-							return gerrors.Wrapf(err, "cannot describe ECS task definitions")
-						}
+						imageSet.add(image)
+					}
+				}
+			}
+		} else {
+			logger.Debug("List services returned an empty result")
+		}
+	}
 
-						for _, container := range describeTaskDefinitionOutput.TaskDefinition.ContainerDefinitions {
-							image := *container.Image
+	err := u.getEcsTaskUsedImages(ctx, clusterArn, imageSet)
+	if err != nil {
+		return gerrors.Wrapf(err, "error getting images used by ECS tasks in cluster %v", clusterArn)
+	}
 
-							logger.Debug("Found image used by ECS service",
-								"image", image, "ecsService", *service.ServiceName)
+	return nil
+}
 
-							imageSet[image] = struct{}{}
-						}
-					}
-				} else {
-					logger.Debug("List services returned an empty result")
+// getEcsTaskDefinitionImages returns images of the task definition, task definitions shared by many services, clusters
+// or scheduled tasks are described only once.
+func (u *usedImages) getEcsTaskDefinitionImages(ctx context.Context, taskDefinition string) ([]string, error) {
+	return u.ecsTaskDefinitions.getImages(ctx, taskDefinition, u.describeEcsTaskDefinitionImages)
+}
+
+func (u *usedImages) describeEcsTaskDefinitionImages(ctx context.Context, taskDefinition string) ([]string, error) {
+	ecsClient := u.awsProvider.EcsClient
+
+	describeTaskDefinitionOutput, err := ecsClient.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String(taskDefinition),
+	})
+	if err != nil {
+		return nil, gerrors.Wrapf(err, "cannot describe ECS task definition %v", taskDefinition)
+	}
+
+	containerDefinitions := describeTaskDefinitionOutput.TaskDefinition.ContainerDefinitions
+	images := make([]string, 0, len(containerDefinitions))
+	for _, container := range containerDefinitions {
+		images = append(images, *container.Image)
+	}
+
+	return images, nil
+}
+
+// ecsTaskDefinitionCache caches images of ECS task definitions (task definition revisions are immutable), concurrent
+// callers requesting the same task definition wait for the first one instead of describing it again.
+type ecsTaskDefinitionCache struct {
+	lock    sync.Mutex
+	entries map[string]*ecsTaskDefinitionCacheEntry
+}
+
+type ecsTaskDefinitionCacheEntry struct {
+	ready  chan struct{}
+	images []string
+	err    error
+}
+
+func newEcsTaskDefinitionCache() *ecsTaskDefinitionCache {
+	return &ecsTaskDefinitionCache{
+		entries: make(map[string]*ecsTaskDefinitionCacheEntry),
+	}
+}
+
+func (c *ecsTaskDefinitionCache) getImages(
+	ctx context.Context,
+	taskDefinition string,
+	describe func(ctx context.Context, taskDefinition string) ([]string, error),
+) ([]string, error) {
+	c.lock.Lock()
+	entry, found := c.entries[taskDefinition]
+	if !found {
+		entry = &ecsTaskDefinitionCacheEntry{
+			ready: make(chan struct{}),
+		}
+		c.entries[taskDefinition] = entry
+	}
+	c.lock.Unlock()
+
+	if !found {
+		entry.images, entry.err = describe(ctx, taskDefinition)
+		close(entry.ready)
+		return entry.images, entry.err
+	}
+
+	select {
+	case <-entry.ready:
+		return entry.images, entry.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// getEcsServiceTaskDefinitions returns the current task definition of the service together with task definitions
+// of all its deployments and task sets, so images needed by an in-flight deployment or a rollback are protected too.
+func getEcsServiceTaskDefinitions(service ecstypes.Service) []string {
+	taskDefinitions := make([]string, 0, 1+len(service.Deployments)+len(service.TaskSets))
+	seen := make(map[string]struct{}, cap(taskDefinitions))
+
+	addTaskDefinition := func(taskDefinition *string) {
+		if taskDefinition == nil {
+			return
+		}
+		if _, ok := seen[*taskDefinition]; ok {
+			return
+		}
+		seen[*taskDefinition] = struct{}{}
+		taskDefinitions = append(taskDefinitions, *taskDefinition)
+	}
+
+	addTaskDefinition(service.TaskDefinition)
+	for _, deployment := range service.Deployments {
+		addTaskDefinition(deployment.TaskDefinition)
+	}
+	for _, taskSet := range service.TaskSets {
+		addTaskDefinition(taskSet.TaskDefinition)
+	}
+
+	return taskDefinitions
+}
+
+// getEcsTaskUsedImages collects images of all running and pending tasks in the cluster, including standalone tasks
+// started with RunTask that are not owned by any service.
+func (u *usedImages) getEcsTaskUsedImages(ctx context.Context, clusterArn string, imageSet *usedImageSet) error {
+	ecsPaginators := u.awsProvider.EcsPaginators
+	ecsClient := u.awsProvider.EcsClient
+
+	listTasksPaginator := ecsPaginators.NewListTasksPaginator(&ecs.ListTasksInput{
+		Cluster:       aws.String(clusterArn),
+		DesiredStatus: ecstypes.DesiredStatusRunning,
+	})
+	for listTasksPaginator.HasMorePages() {
+		listTasksPage, err := listTasksPaginator.NextPage(ctx)
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot get list ECS tasks page")
+		}
+
+		if len(listTasksPage.TaskArns) == 0 {
+			logger.Debug("List tasks returned an empty result")
+			continue
+		}
+
+		describeTasksOutput, err := ecsClient.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+			Tasks:   listTasksPage.TaskArns,
+			Cluster: aws.String(clusterArn),
+		})
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot describe ECS tasks")
+		}
+
+		for _, task := range describeTasksOutput.Tasks {
+			for _, container := range task.Containers {
+				if container.Image == nil {
+					continue
+				}
+				image := *container.Image
+
+				logger.Debug("Found image used by ECS task",
+					"image", image, "ecsTask", *task.TaskArn)
+
+				imageSet.add(image)
+
+				if container.ImageDigest != nil {
+					digestImage := imageDigestReference(image, *container.ImageDigest)
+
+					logger.Debug("Found image digest used by ECS task",
+						"image", digestImage, "ecsTask", *task.TaskArn)
+
+					imageSet.add(digestImage)
 				}
 			}
 		}
@@ -111,13 +419,24 @@ func (u *usedImages) getEcsUsedImages(imageSet map[string]struct{}) error {
 	return nil
 }
 
-func (u *usedImages) getLambdaUsedImages(imageSet map[string]struct{}) error {
+// imageDigestReference converts an image reference (e.g. repository:tag) into the repository@digest form.
+func imageDigestReference(image string, digest string) string {
+	repository := image
+	if i := strings.Index(repository, "@"); i >= 0 {
+		repository = repository[:i]
+	}
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository = repository[:i]
+	}
+	return fmt.Sprintf("%v@%v", repository, digest)
+}
+
+func (u *usedImages) getLambdaUsedImages(ctx context.Context, imageSet *usedImageSet) error {
 	lambdaPaginators := u.awsProvider.LambdaPaginators
-	lambdaClient := u.awsProvider.LambdaClient
 
 	listFunctionsPaginator := lambdaPaginators.NewListFunctionsPaginator(&lambda.ListFunctionsInput{})
 	for listFunctionsPaginator.HasMorePages() {
-		page, err := listFunctionsPaginator.NextPage(context.TODO())
+		page, err := listFunctionsPaginator.NextPage(ctx)
 		if err != nil {
 			return gerrors.Wrapf(err, "cannot get list Lambda functions page")
 		}
@@ -126,19 +445,28 @@ func (u *usedImages) getLambdaUsedImages(imageSet map[string]struct{}) error {
 
 			if lambdaFunction.PackageType == lambdatypes.PackageTypeImage {
 
-				getFunctionOutput, err := lambdaClient.GetFunction(context.TODO(), &lambda.GetFunctionInput{
-					FunctionName: lambdaFunction.FunctionArn,
-				})
+				err := u.getLambdaVersionUsedImages(ctx, lambdaFunction, nil, imageSet)
 				if err != nil {
-					return gerrors.Wrapf(err, "cannot get Lambda function")
+					return gerrors.Wrapf(err, "cannot get image of Lambda function %v", *lambdaFunction.FunctionName)
 				}
 
-				image := *getFunctionOutput.Code.ImageUri
-
-				logger.Debug("Found image used by Lambda",
-					"image", image, "lambda", *lambdaFunction.FunctionName)
+				var versions []string
+				if u.config.LambdaReferencedVersionsOnly {
+					versions, err = u.getLambdaReferencedVersions(ctx, *lambdaFunction.FunctionArn)
+				} else {
+					versions, err = u.getLambdaPublishedVersions(ctx, *lambdaFunction.FunctionArn)
+				}
+				if err != nil {
+					return gerrors.Wrapf(err, "cannot get versions of Lambda function %v", *lambdaFunction.FunctionName)
+				}
 
-				imageSet[image] = struct{}{}
+				for _, version := range versions {
+					err := u.getLambdaVersionUsedImages(ctx, lambdaFunction, aws.String(version), imageSet)
+					if err != nil {
+						return gerrors.Wrapf(err, "cannot get image of Lambda function %v version %v",
+							*lambdaFunction.FunctionName, version)
+					}
+				}
 			}
 		}
 	}
@@ -146,20 +474,166 @@ func (u *usedImages) getLambdaUsedImages(imageSet map[string]struct{}) error {
 	return nil
 }
 
-func (u *usedImages) getAppRunnerUsedImages(imageSet map[string]struct{}) error {
+// getLambdaVersionUsedImages collects the image of a single Lambda function version, a nil version means $LATEST.
+func (u *usedImages) getLambdaVersionUsedImages(
+	ctx context.Context,
+	lambdaFunction lambdatypes.FunctionConfiguration,
+	version *string,
+	imageSet *usedImageSet,
+) error {
+	lambdaClient := u.awsProvider.LambdaClient
+
+	getFunctionOutput, err := lambdaClient.GetFunction(ctx, &lambda.GetFunctionInput{
+		FunctionName: lambdaFunction.FunctionArn,
+		Qualifier:    version,
+	})
+	if err != nil {
+		return gerrors.Wrapf(err, "cannot get Lambda function")
+	}
+
+	lambdaVersion := LambdaLatestVersion
+	if version != nil {
+		lambdaVersion = *version
+	}
+
+	image := *getFunctionOutput.Code.ImageUri
+
+	logger.Debug("Found image used by Lambda",
+		"image", image, "lambda", *lambdaFunction.FunctionName, "lambdaVersion", lambdaVersion)
+
+	imageSet.add(image)
+
+	if getFunctionOutput.Code.ResolvedImageUri != nil {
+		resolvedImage := *getFunctionOutput.Code.ResolvedImageUri
+
+		logger.Debug("Found image digest used by Lambda",
+			"image", resolvedImage, "lambda", *lambdaFunction.FunctionName, "lambdaVersion", lambdaVersion)
+
+		imageSet.add(resolvedImage)
+	}
+
+	return nil
+}
+
+// getLambdaPublishedVersions returns all published versions of the function, except $LATEST.
+func (u *usedImages) getLambdaPublishedVersions(ctx context.Context, functionArn string) ([]string, error) {
+	lambdaPaginators := u.awsProvider.LambdaPaginators
+
+	var versions []string
+
+	listVersionsByFunctionPaginator := lambdaPaginators.NewListVersionsByFunctionPaginator(&lambda.ListVersionsByFunctionInput{
+		FunctionName: aws.String(functionArn),
+	})
+	for listVersionsByFunctionPaginator.HasMorePages() {
+		page, err := listVersionsByFunctionPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, gerrors.Wrapf(err, "cannot get list Lambda versions by function page")
+		}
+
+		for _, version := range page.Versions {
+			if *version.Version != LambdaLatestVersion {
+				versions = append(versions, *version.Version)
+			}
+		}
+	}
+
+	return versions, nil
+}
+
+// getLambdaReferencedVersions returns published versions of the function that are referenced by an alias (including
+// its weighted routing configuration) or have provisioned concurrency configured.
+func (u *usedImages) getLambdaReferencedVersions(ctx context.Context, functionArn string) ([]string, error) {
+	lambdaPaginators := u.awsProvider.LambdaPaginators
+
+	var versions []string
+	seenVersions := make(map[string]struct{})
+	addVersion := func(version string) {
+		if _, ok := seenVersions[version]; ok || version == LambdaLatestVersion {
+			return
+		}
+		seenVersions[version] = struct{}{}
+		versions = append(versions, version)
+	}
+
+	aliasVersions := make(map[string]string)
+
+	listAliasesPaginator := lambdaPaginators.NewListAliasesPaginator(&lambda.ListAliasesInput{
+		FunctionName: aws.String(functionArn),
+	})
+	for listAliasesPaginator.HasMorePages() {
+		page, err := listAliasesPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, gerrors.Wrapf(err, "cannot get list Lambda aliases page")
+		}
+
+		for _, alias := range page.Aliases {
+			aliasVersions[*alias.Name] = *alias.FunctionVersion
+			addVersion(*alias.FunctionVersion)
+
+			if alias.RoutingConfig != nil {
+				additionalVersions := make([]string, 0, len(alias.RoutingConfig.AdditionalVersionWeights))
+				for additionalVersion := range alias.RoutingConfig.AdditionalVersionWeights {
+					additionalVersions = append(additionalVersions, additionalVersion)
+				}
+				sort.Strings(additionalVersions)
+
+				for _, additionalVersion := range additionalVersions {
+					addVersion(additionalVersion)
+				}
+			}
+		}
+	}
+
+	listProvisionedConcurrencyConfigsPaginator := lambdaPaginators.NewListProvisionedConcurrencyConfigsPaginator(
+		&lambda.ListProvisionedConcurrencyConfigsInput{
+			FunctionName: aws.String(functionArn),
+		})
+	for listProvisionedConcurrencyConfigsPaginator.HasMorePages() {
+		page, err := listProvisionedConcurrencyConfigsPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, gerrors.Wrapf(err, "cannot get list Lambda provisioned concurrency configs page")
+		}
+
+		for _, provisionedConcurrencyConfig := range page.ProvisionedConcurrencyConfigs {
+			qualifier := getLambdaQualifier(*provisionedConcurrencyConfig.FunctionArn)
+			if qualifier == "" {
+				continue
+			}
+			// provisioned concurrency can be configured either on a version or on an alias
+			if aliasVersion, ok := aliasVersions[qualifier]; ok {
+				qualifier = aliasVersion
+			}
+			addVersion(qualifier)
+		}
+	}
+
+	return versions, nil
+}
+
+// getLambdaQualifier returns the version or alias of a qualified Lambda function ARN
+// (arn:aws:lambda:region:account:function:name:qualifier) or an empty string if the ARN is not qualified.
+func getLambdaQualifier(functionArn string) string {
+	parts := strings.Split(functionArn, ":")
+	if len(parts) != 8 {
+		return ""
+	}
+	return parts[7]
+}
+
+func (u *usedImages) getAppRunnerUsedImages(ctx context.Context, imageSet *usedImageSet) error {
 	appRunnerPaginators := u.awsProvider.AppRunnerPaginators
 	appRunnerClient := u.awsProvider.AppRunnerClient
 
 	listServicesPaginator := appRunnerPaginators.NewListServicesPaginator(&apprunner.ListServicesInput{})
 
 	for listServicesPaginator.HasMorePages() {
-		page, err := listServicesPaginator.NextPage(context.TODO())
+		page, err := listServicesPaginator.NextPage(ctx)
 		if err != nil {
 			return gerrors.Wrapf(err, "cannot get list App Runner services page")
 		}
 
 		for _, serviceSummary := range page.ServiceSummaryList {
-			describeServiceOutput, err := appRunnerClient.DescribeService(context.TODO(), &apprunner.DescribeServiceInput{
+			describeServiceOutput, err := appRunnerClient.DescribeService(ctx, &apprunner.DescribeServiceInput{
 				ServiceArn: serviceSummary.ServiceArn,
 			})
 			if err != nil {
@@ -173,7 +647,7 @@ func (u *usedImages) getAppRunnerUsedImages(imageSet map[string]struct{}) error
 				logger.Debug("Found image used by App Runner",
 					"image", image, "appRunnerService", *serviceSummary.ServiceName)
 
-				imageSet[image] = struct{}{}
+				imageSet.add(image)
 			}
 		}
 
@@ -182,7 +656,7 @@ func (u *usedImages) getAppRunnerUsedImages(imageSet map[string]struct{}) error
 	return nil
 }
 
-func (u *usedImages) checkAppRunnerEnabledInRegion() (bool, error) {
+func (u *usedImages) checkAppRunnerEnabledInRegion(ctx context.Context) (bool, error) {
 	ssmPaginators := u.awsProvider.SsmPaginators
 	awsRegion := u.awsProvider.Region
 
@@ -191,7 +665,7 @@ func (u *usedImages) checkAppRunnerEnabledInRegion() (bool, error) {
 	})
 
 	for getParametersByPathPaginator.HasMorePages() {
-		page, err := getParametersByPathPaginator.NextPage(context.TODO())
+		page, err := getParametersByPathPaginator.NextPage(ctx)
 		if err != nil {
 			return false, gerrors.Wrapf(err, "cannot get get ssm parameters by path page")
 		}
@@ -0,0 +1,168 @@
+package cleaner
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/batch"
+	batchtypes "github.com/aws/aws-sdk-go-v2/service/batch/types"
+	gerrors "github.com/pkg/errors"
+)
+
+const batchJobDefinitionStatusActive = "ACTIVE"
+
+// batchUnfinishedJobStatuses are statuses of jobs that are running or can still start (and pull their images).
+var batchUnfinishedJobStatuses = []batchtypes.JobStatus{
+	batchtypes.JobStatusSubmitted,
+	batchtypes.JobStatusPending,
+	batchtypes.JobStatusRunnable,
+	batchtypes.JobStatusStarting,
+	batchtypes.JobStatusRunning,
+}
+
+func (u *usedImages) getBatchUsedImages(ctx context.Context, imageSet *usedImageSet) error {
+	err := u.getBatchJobDefinitionsUsedImages(ctx, imageSet)
+	if err != nil {
+		return gerrors.Wrapf(err, "error getting images used by Batch job definitions")
+	}
+
+	err = u.getBatchJobsUsedImages(ctx, imageSet)
+	if err != nil {
+		return gerrors.Wrapf(err, "error getting images used by Batch jobs")
+	}
+
+	return nil
+}
+
+func (u *usedImages) getBatchJobDefinitionsUsedImages(ctx context.Context, imageSet *usedImageSet) error {
+	batchPaginators := u.awsProvider.BatchPaginators
+
+	describeJobDefinitionsPaginator := batchPaginators.NewDescribeJobDefinitionsPaginator(&batch.DescribeJobDefinitionsInput{
+		Status: aws.String(batchJobDefinitionStatusActive),
+	})
+	for describeJobDefinitionsPaginator.HasMorePages() {
+		page, err := describeJobDefinitionsPaginator.NextPage(ctx)
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot get describe Batch job definitions page")
+		}
+
+		for _, jobDefinition := range page.JobDefinitions {
+			images := getBatchImages(jobDefinition.ContainerProperties, jobDefinition.NodeProperties)
+			if jobDefinition.EksProperties != nil && jobDefinition.EksProperties.PodProperties != nil {
+				for _, container := range jobDefinition.EksProperties.PodProperties.Containers {
+					images = append(images, container.Image)
+				}
+			}
+
+			for _, image := range images {
+				if image == nil {
+					continue
+				}
+
+				logger.Debug("Found image used by Batch job definition",
+					"image", *image, "batchJobDefinition", *jobDefinition.JobDefinitionArn)
+
+				imageSet.add(*image)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (u *usedImages) getBatchJobsUsedImages(ctx context.Context, imageSet *usedImageSet) error {
+	batchPaginators := u.awsProvider.BatchPaginators
+
+	describeJobQueuesPaginator := batchPaginators.NewDescribeJobQueuesPaginator(&batch.DescribeJobQueuesInput{})
+	for describeJobQueuesPaginator.HasMorePages() {
+		page, err := describeJobQueuesPaginator.NextPage(ctx)
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot get describe Batch job queues page")
+		}
+
+		for _, jobQueue := range page.JobQueues {
+			for _, jobStatus := range batchUnfinishedJobStatuses {
+				err := u.getBatchJobQueueUsedImages(ctx, *jobQueue.JobQueueArn, jobStatus, imageSet)
+				if err != nil {
+					return gerrors.Wrapf(err, "error getting images used by %v jobs in Batch job queue %v",
+						jobStatus, *jobQueue.JobQueueName)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (u *usedImages) getBatchJobQueueUsedImages(ctx context.Context, jobQueueArn string, jobStatus batchtypes.JobStatus, imageSet *usedImageSet) error {
+	batchPaginators := u.awsProvider.BatchPaginators
+	batchClient := u.awsProvider.BatchClient
+
+	listJobsPaginator := batchPaginators.NewListJobsPaginator(&batch.ListJobsInput{
+		JobQueue:  aws.String(jobQueueArn),
+		JobStatus: jobStatus,
+	})
+	for listJobsPaginator.HasMorePages() {
+		page, err := listJobsPaginator.NextPage(ctx)
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot get list Batch jobs page")
+		}
+
+		if len(page.JobSummaryList) == 0 {
+			continue
+		}
+
+		jobIds := make([]string, 0, len(page.JobSummaryList))
+		for _, jobSummary := range page.JobSummaryList {
+			jobIds = append(jobIds, *jobSummary.JobId)
+		}
+
+		describeJobsOutput, err := batchClient.DescribeJobs(ctx, &batch.DescribeJobsInput{
+			Jobs: jobIds,
+		})
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot describe Batch jobs")
+		}
+
+		for _, job := range describeJobsOutput.Jobs {
+			var images []*string
+			if job.Container != nil {
+				images = append(images, job.Container.Image)
+			}
+			images = append(images, getBatchImages(nil, job.NodeProperties)...)
+			if job.EksProperties != nil && job.EksProperties.PodProperties != nil {
+				for _, container := range job.EksProperties.PodProperties.Containers {
+					images = append(images, container.Image)
+				}
+			}
+
+			for _, image := range images {
+				if image == nil {
+					continue
+				}
+
+				logger.Debug("Found image used by Batch job",
+					"image", *image, "batchJob", *job.JobId)
+
+				imageSet.add(*image)
+			}
+		}
+	}
+
+	return nil
+}
+
+// getBatchImages returns images of single-node container properties and of all multi-node ranges.
+func getBatchImages(containerProperties *batchtypes.ContainerProperties, nodeProperties *batchtypes.NodeProperties) []*string {
+	var images []*string
+	if containerProperties != nil {
+		images = append(images, containerProperties.Image)
+	}
+	if nodeProperties != nil {
+		for _, nodeRangeProperty := range nodeProperties.NodeRangeProperties {
+			if nodeRangeProperty.Container != nil {
+				images = append(images, nodeRangeProperty.Container.Image)
+			}
+		}
+	}
+	return images
+}
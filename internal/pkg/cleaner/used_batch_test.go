@@ -0,0 +1,212 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/batch"
+	batchtypes "github.com/aws/aws-sdk-go-v2/service/batch/types"
+	boxaws "github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+)
+
+func TestGetBatchUsedImages(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockAwsProvider := boxaws.NewMockProvider(ctrl)
+
+	mockSsm(ctrl, mockAwsProvider, [][]string{}, true)
+	mockEcs(ctrl, mockAwsProvider, []map[string][]map[string]string{})
+	mockLambda(ctrl, mockAwsProvider, []map[string]lambdaMockResult{})
+
+	mockBatchJobDefinitions(ctrl, mockAwsProvider, [][]batchtypes.JobDefinition{
+		{
+			{
+				JobDefinitionArn: aws.String("jobDefinition1Arn"),
+				ContainerProperties: &batchtypes.ContainerProperties{
+					Image: aws.String("jobDefinition1:v1"),
+				},
+			},
+			{
+				JobDefinitionArn: aws.String("jobDefinition2Arn"),
+				NodeProperties: &batchtypes.NodeProperties{
+					NodeRangeProperties: []batchtypes.NodeRangeProperty{
+						{
+							Container: &batchtypes.ContainerProperties{
+								Image: aws.String("jobDefinition2Main:v1"),
+							},
+						},
+						{
+							Container: &batchtypes.ContainerProperties{
+								Image: aws.String("jobDefinition2Worker:v1"),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			{
+				JobDefinitionArn: aws.String("jobDefinition3Arn"),
+				EksProperties: &batchtypes.EksProperties{
+					PodProperties: &batchtypes.EksPodProperties{
+						Containers: []batchtypes.EksContainer{
+							{
+								Image: aws.String("jobDefinition3:v1"),
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	mockBatchJobs(ctrl, mockAwsProvider, map[string]map[batchtypes.JobStatus][]batchtypes.JobDetail{
+		"jobQueue1": {
+			batchtypes.JobStatusRunnable: {
+				{
+					JobId: aws.String("job1"),
+					Container: &batchtypes.ContainerDetail{
+						Image: aws.String("job1:v1"),
+					},
+				},
+			},
+			batchtypes.JobStatusRunning: {
+				{
+					JobId: aws.String("job2"),
+					EksProperties: &batchtypes.EksPropertiesDetail{
+						PodProperties: &batchtypes.EksPodPropertiesDetail{
+							Containers: []batchtypes.EksContainerDetail{
+								{
+									Image: aws.String("job2:v1"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"jobQueue2": {
+			batchtypes.JobStatusStarting: {
+				{
+					JobId: aws.String("job3"),
+					NodeProperties: &batchtypes.NodeProperties{
+						NodeRangeProperties: []batchtypes.NodeRangeProperty{
+							{
+								Container: &batchtypes.ContainerProperties{
+									Image: aws.String("job3:v1"),
+								},
+							},
+						},
+					},
+				},
+				{
+					JobId: aws.String("job4"),
+					Container: &batchtypes.ContainerDetail{
+						Image: aws.String("jobDefinition1:v1"),
+					},
+				},
+			},
+		},
+	})
+
+	expectedImages := map[string]struct{}{
+		"jobDefinition1:v1":       {},
+		"jobDefinition2Main:v1":   {},
+		"jobDefinition2Worker:v1": {},
+		"jobDefinition3:v1":       {},
+		"job1:v1":                 {},
+		"job2:v1":                 {},
+		"job3:v1":                 {},
+	}
+
+	images, err := (&usedImages{
+		awsProvider: mockAwsProvider.Provider,
+		config: Config{
+			BatchEnabled: true,
+		},
+	}).getImages(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := cmp.Diff(
+		expectedImages,
+		images,
+	)
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func mockBatchJobDefinitions(ctrl *gomock.Controller, mockAwsProvider *boxaws.MockProvider, mockResult [][]batchtypes.JobDefinition) {
+	mockDescribeJobDefinitionsPaginator := boxaws.NewMockBatchDescribeJobDefinitionsPaginator(ctrl)
+	mockAwsProvider.MockBatchPaginators.EXPECT().NewDescribeJobDefinitionsPaginator(&batch.DescribeJobDefinitionsInput{
+		Status: aws.String("ACTIVE"),
+	}).Return(mockDescribeJobDefinitionsPaginator)
+
+	for _, jobDefinitionsPage := range mockResult {
+		mockDescribeJobDefinitionsPaginator.EXPECT().HasMorePages().Return(true)
+		mockDescribeJobDefinitionsPaginator.EXPECT().NextPage(gomock.Any()).Return(&batch.DescribeJobDefinitionsOutput{
+			JobDefinitions: jobDefinitionsPage,
+		}, nil)
+	}
+	mockDescribeJobDefinitionsPaginator.EXPECT().HasMorePages().Return(false)
+}
+
+func mockBatchJobs(ctrl *gomock.Controller, mockAwsProvider *boxaws.MockProvider, mockResult map[string]map[batchtypes.JobStatus][]batchtypes.JobDetail) {
+	mockDescribeJobQueuesPaginator := boxaws.NewMockBatchDescribeJobQueuesPaginator(ctrl)
+	mockAwsProvider.MockBatchPaginators.EXPECT().NewDescribeJobQueuesPaginator(gomock.Any()).Return(mockDescribeJobQueuesPaginator)
+
+	jobQueues := make([]batchtypes.JobQueueDetail, 0, len(mockResult))
+	for jobQueueName := range mockResult {
+		jobQueues = append(jobQueues, batchtypes.JobQueueDetail{
+			JobQueueName: aws.String(jobQueueName),
+			JobQueueArn:  aws.String(fmt.Sprintf("%vArn", jobQueueName)),
+		})
+	}
+
+	mockDescribeJobQueuesPaginator.EXPECT().HasMorePages().Return(true)
+	mockDescribeJobQueuesPaginator.EXPECT().NextPage(gomock.Any()).Return(&batch.DescribeJobQueuesOutput{
+		JobQueues: jobQueues,
+	}, nil)
+	mockDescribeJobQueuesPaginator.EXPECT().HasMorePages().Return(false)
+
+	for _, jobQueue := range jobQueues {
+		for _, jobStatus := range batchUnfinishedJobStatuses {
+			jobs := mockResult[*jobQueue.JobQueueName][jobStatus]
+
+			mockListJobsPaginator := boxaws.NewMockBatchListJobsPaginator(ctrl)
+			mockAwsProvider.MockBatchPaginators.EXPECT().NewListJobsPaginator(&batch.ListJobsInput{
+				JobQueue:  jobQueue.JobQueueArn,
+				JobStatus: jobStatus,
+			}).Return(mockListJobsPaginator)
+
+			jobSummaries := make([]batchtypes.JobSummary, 0, len(jobs))
+			jobIds := make([]string, 0, len(jobs))
+			for _, job := range jobs {
+				jobSummaries = append(jobSummaries, batchtypes.JobSummary{
+					JobId: job.JobId,
+				})
+				jobIds = append(jobIds, *job.JobId)
+			}
+
+			mockListJobsPaginator.EXPECT().HasMorePages().Return(true)
+			mockListJobsPaginator.EXPECT().NextPage(gomock.Any()).Return(&batch.ListJobsOutput{
+				JobSummaryList: jobSummaries,
+			}, nil)
+			mockListJobsPaginator.EXPECT().HasMorePages().Return(false)
+
+			if len(jobs) > 0 {
+				mockAwsProvider.MockBatchClient.EXPECT().DescribeJobs(gomock.Any(), &batch.DescribeJobsInput{
+					Jobs: jobIds,
+				}).Return(&batch.DescribeJobsOutput{
+					Jobs: jobs,
+				}, nil)
+			}
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package cleaner
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codebuild"
+	codebuildtypes "github.com/aws/aws-sdk-go-v2/service/codebuild/types"
+	gerrors "github.com/pkg/errors"
+	"time"
+)
+
+// codeBuildMaxBuildDuration is the longest time a build can stay in progress: the maximum queued timeout (8 hours) plus
+// the maximum build timeout (36 hours).
+const codeBuildMaxBuildDuration = 44 * time.Hour
+
+func (u *usedImages) getCodeBuildUsedImages(ctx context.Context, imageSet *usedImageSet) error {
+	err := u.getCodeBuildProjectsUsedImages(ctx, imageSet)
+	if err != nil {
+		return gerrors.Wrapf(err, "error getting images used by CodeBuild projects")
+	}
+
+	err = u.getCodeBuildBuildBatchesUsedImages(ctx, imageSet)
+	if err != nil {
+		return gerrors.Wrapf(err, "error getting images used by CodeBuild batch builds")
+	}
+
+	return nil
+}
+
+func (u *usedImages) getCodeBuildProjectsUsedImages(ctx context.Context, imageSet *usedImageSet) error {
+	codeBuildPaginators := u.awsProvider.CodeBuildPaginators
+	codeBuildClient := u.awsProvider.CodeBuildClient
+
+	// a page has at most 100 projects, which is also the limit of a single BatchGetProjects call
+	listProjectsPaginator := codeBuildPaginators.NewListProjectsPaginator(&codebuild.ListProjectsInput{})
+	for listProjectsPaginator.HasMorePages() {
+		page, err := listProjectsPaginator.NextPage(ctx)
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot get list CodeBuild projects page")
+		}
+
+		if len(page.Projects) == 0 {
+			continue
+		}
+
+		batchGetProjectsOutput, err := codeBuildClient.BatchGetProjects(ctx, &codebuild.BatchGetProjectsInput{
+			Names: page.Projects,
+		})
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot get CodeBuild projects")
+		}
+
+		for _, project := range batchGetProjectsOutput.Projects {
+			addCodeBuildEnvironmentImage(project.Environment, "codeBuildProject", *project.Name, imageSet)
+
+			err := u.getCodeBuildProjectBuildsUsedImages(ctx, *project.Name, imageSet)
+			if err != nil {
+				return gerrors.Wrapf(err, "cannot get builds of CodeBuild project %v", *project.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// getCodeBuildProjectBuildsUsedImages collects images of builds of the project in progress, their environment can be
+// different from the current environment of the project (e.g. overridden when the build was started). Builds are listed
+// from the newest one and the listing stops at a build started before any build still in progress could have been.
+func (u *usedImages) getCodeBuildProjectBuildsUsedImages(ctx context.Context, projectName string, imageSet *usedImageSet) error {
+	codeBuildPaginators := u.awsProvider.CodeBuildPaginators
+	codeBuildClient := u.awsProvider.CodeBuildClient
+
+	minStartTime := u.startTime.Add(-codeBuildMaxBuildDuration)
+
+	listBuildsForProjectPaginator := codeBuildPaginators.NewListBuildsForProjectPaginator(&codebuild.ListBuildsForProjectInput{
+		ProjectName: aws.String(projectName),
+		SortOrder:   codebuildtypes.SortOrderTypeDescending,
+	})
+	for listBuildsForProjectPaginator.HasMorePages() {
+		page, err := listBuildsForProjectPaginator.NextPage(ctx)
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot get list CodeBuild builds page")
+		}
+
+		if len(page.Ids) == 0 {
+			continue
+		}
+
+		batchGetBuildsOutput, err := codeBuildClient.BatchGetBuilds(ctx, &codebuild.BatchGetBuildsInput{
+			Ids: page.Ids,
+		})
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot get CodeBuild builds")
+		}
+
+		oldBuildFound := false
+		for _, build := range batchGetBuildsOutput.Builds {
+			if build.BuildStatus == codebuildtypes.StatusTypeInProgress {
+				addCodeBuildEnvironmentImage(build.Environment, "codeBuildBuild", *build.Id, imageSet)
+			}
+			if build.StartTime != nil && build.StartTime.Before(minStartTime) {
+				oldBuildFound = true
+			}
+		}
+		if oldBuildFound {
+			break
+		}
+	}
+
+	return nil
+}
+
+// getCodeBuildBuildBatchesUsedImages collects images of batch builds in progress, their environment can be different
+// from the current environment of the project (e.g. overridden when the batch build was started).
+func (u *usedImages) getCodeBuildBuildBatchesUsedImages(ctx context.Context, imageSet *usedImageSet) error {
+	codeBuildPaginators := u.awsProvider.CodeBuildPaginators
+	codeBuildClient := u.awsProvider.CodeBuildClient
+
+	listBuildBatchesPaginator := codeBuildPaginators.NewListBuildBatchesPaginator(&codebuild.ListBuildBatchesInput{
+		Filter: &codebuildtypes.BuildBatchFilter{
+			Status: codebuildtypes.StatusTypeInProgress,
+		},
+	})
+	for listBuildBatchesPaginator.HasMorePages() {
+		page, err := listBuildBatchesPaginator.NextPage(ctx)
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot get list CodeBuild batch builds page")
+		}
+
+		if len(page.Ids) == 0 {
+			continue
+		}
+
+		batchGetBuildBatchesOutput, err := codeBuildClient.BatchGetBuildBatches(ctx, &codebuild.BatchGetBuildBatchesInput{
+			Ids: page.Ids,
+		})
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot get CodeBuild batch builds")
+		}
+
+		for _, buildBatch := range batchGetBuildBatchesOutput.BuildBatches {
+			addCodeBuildEnvironmentImage(buildBatch.Environment, "codeBuildBatchBuild", *buildBatch.Id, imageSet)
+		}
+	}
+
+	return nil
+}
+
+func addCodeBuildEnvironmentImage(
+	environment *codebuildtypes.ProjectEnvironment,
+	resourceKey string,
+	resourceName string,
+	imageSet *usedImageSet,
+) {
+	if environment == nil || environment.Image == nil {
+		return
+	}
+
+	logger.Debug("Found image used by CodeBuild", "image", *environment.Image, resourceKey, resourceName)
+
+	imageSet.add(*environment.Image)
+}
@@ -0,0 +1,230 @@
+package cleaner
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codebuild"
+	codebuildtypes "github.com/aws/aws-sdk-go-v2/service/codebuild/types"
+	boxaws "github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+)
+
+func TestGetCodeBuildUsedImages(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockAwsProvider := boxaws.NewMockProvider(ctrl)
+
+	mockSsm(ctrl, mockAwsProvider, [][]string{}, true)
+	mockEcs(ctrl, mockAwsProvider, []map[string][]map[string]string{})
+	mockLambda(ctrl, mockAwsProvider, []map[string]lambdaMockResult{})
+
+	mockCodeBuildProjects(ctrl, mockAwsProvider, [][]codebuildtypes.Project{
+		{
+			{
+				Name: aws.String("project1"),
+				Environment: &codebuildtypes.ProjectEnvironment{
+					Image: aws.String("project1:v1"),
+				},
+			},
+			{
+				Name: aws.String("project2"),
+				Environment: &codebuildtypes.ProjectEnvironment{
+					Image: aws.String("aws/codebuild/standard:6.0"),
+				},
+			},
+		},
+		{},
+		{
+			{
+				Name: aws.String("project3"),
+				Environment: &codebuildtypes.ProjectEnvironment{
+					Image: aws.String("project3:v1"),
+				},
+			},
+		},
+	}, map[string]codeBuildBuildsMockResult{
+		"project1": {
+			pages: [][]codebuildtypes.Build{
+				{
+					{
+						Id:          aws.String("project1:build4"),
+						BuildStatus: codebuildtypes.StatusTypeInProgress,
+						StartTime:   aws.Time(testTimeParse(t, "2022-08-30T23:00:00Z")),
+						Environment: &codebuildtypes.ProjectEnvironment{
+							Image: aws.String("project1:v2"),
+						},
+					},
+					{
+						Id:          aws.String("project1:build3"),
+						BuildStatus: codebuildtypes.StatusTypeSucceeded,
+						StartTime:   aws.Time(testTimeParse(t, "2022-08-30T22:00:00Z")),
+						Environment: &codebuildtypes.ProjectEnvironment{
+							Image: aws.String("project1:finished"),
+						},
+					},
+				},
+				{
+					{
+						Id:          aws.String("project1:build2"),
+						BuildStatus: codebuildtypes.StatusTypeInProgress,
+						StartTime:   aws.Time(testTimeParse(t, "2022-08-29T18:00:00Z")),
+						Environment: &codebuildtypes.ProjectEnvironment{
+							Image: aws.String("project1:v1-override"),
+						},
+					},
+					{
+						Id:          aws.String("project1:build1"),
+						BuildStatus: codebuildtypes.StatusTypeSucceeded,
+						StartTime:   aws.Time(testTimeParse(t, "2022-08-28T22:00:00Z")),
+						Environment: &codebuildtypes.ProjectEnvironment{
+							Image: aws.String("project1:finished"),
+						},
+					},
+				},
+			},
+			// the listing stops at the build started before any build in progress could have been
+			stopped: true,
+		},
+	})
+
+	mockCodeBuildBuildBatches(ctrl, mockAwsProvider, []codebuildtypes.BuildBatch{
+		{
+			Id: aws.String("project1:buildBatch1"),
+			Environment: &codebuildtypes.ProjectEnvironment{
+				Image: aws.String("project1:v0"),
+			},
+		},
+	})
+
+	expectedImages := map[string]struct{}{
+		"project1:v1":                {},
+		"aws/codebuild/standard:6.0": {},
+		"project3:v1":                {},
+		"project1:v0":                {},
+		"project1:v2":                {},
+		"project1:v1-override":       {},
+	}
+
+	images, err := (&usedImages{
+		awsProvider: mockAwsProvider.Provider,
+		config: Config{
+			CodeBuildEnabled: true,
+		},
+		startTime: testTimeParse(t, "2022-08-31T00:00:00Z"),
+	}).getImages(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := cmp.Diff(
+		expectedImages,
+		images,
+	)
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+type codeBuildBuildsMockResult struct {
+	pages [][]codebuildtypes.Build
+	// stopped is set if not all pages are expected to be listed
+	stopped bool
+}
+
+func mockCodeBuildProjects(
+	ctrl *gomock.Controller,
+	mockAwsProvider *boxaws.MockProvider,
+	mockResult [][]codebuildtypes.Project,
+	buildsMockResult map[string]codeBuildBuildsMockResult,
+) {
+	mockListProjectsPaginator := boxaws.NewMockCodeBuildListProjectsPaginator(ctrl)
+	mockAwsProvider.MockCodeBuildPaginators.EXPECT().NewListProjectsPaginator(gomock.Any()).Return(mockListProjectsPaginator)
+
+	for _, projectsPage := range mockResult {
+		projectNames := make([]string, 0, len(projectsPage))
+		for _, project := range projectsPage {
+			projectNames = append(projectNames, *project.Name)
+		}
+
+		mockListProjectsPaginator.EXPECT().HasMorePages().Return(true)
+		mockListProjectsPaginator.EXPECT().NextPage(gomock.Any()).Return(&codebuild.ListProjectsOutput{
+			Projects: projectNames,
+		}, nil)
+
+		if len(projectsPage) > 0 {
+			mockAwsProvider.MockCodeBuildClient.EXPECT().BatchGetProjects(gomock.Any(), &codebuild.BatchGetProjectsInput{
+				Names: projectNames,
+			}).Return(&codebuild.BatchGetProjectsOutput{
+				Projects: projectsPage,
+			}, nil)
+		}
+
+		for _, project := range projectsPage {
+			mockCodeBuildProjectBuilds(ctrl, mockAwsProvider, *project.Name, buildsMockResult[*project.Name])
+		}
+	}
+	mockListProjectsPaginator.EXPECT().HasMorePages().Return(false)
+}
+
+func mockCodeBuildProjectBuilds(
+	ctrl *gomock.Controller,
+	mockAwsProvider *boxaws.MockProvider,
+	projectName string,
+	mockResult codeBuildBuildsMockResult,
+) {
+	mockListBuildsForProjectPaginator := boxaws.NewMockCodeBuildListBuildsForProjectPaginator(ctrl)
+	mockAwsProvider.MockCodeBuildPaginators.EXPECT().NewListBuildsForProjectPaginator(&codebuild.ListBuildsForProjectInput{
+		ProjectName: aws.String(projectName),
+		SortOrder:   codebuildtypes.SortOrderTypeDescending,
+	}).Return(mockListBuildsForProjectPaginator)
+
+	for _, buildsPage := range mockResult.pages {
+		buildIds := make([]string, 0, len(buildsPage))
+		for _, build := range buildsPage {
+			buildIds = append(buildIds, *build.Id)
+		}
+
+		mockListBuildsForProjectPaginator.EXPECT().HasMorePages().Return(true)
+		mockListBuildsForProjectPaginator.EXPECT().NextPage(gomock.Any()).Return(&codebuild.ListBuildsForProjectOutput{
+			Ids: buildIds,
+		}, nil)
+
+		mockAwsProvider.MockCodeBuildClient.EXPECT().BatchGetBuilds(gomock.Any(), &codebuild.BatchGetBuildsInput{
+			Ids: buildIds,
+		}).Return(&codebuild.BatchGetBuildsOutput{
+			Builds: buildsPage,
+		}, nil)
+	}
+	if !mockResult.stopped {
+		mockListBuildsForProjectPaginator.EXPECT().HasMorePages().Return(false)
+	}
+}
+
+func mockCodeBuildBuildBatches(ctrl *gomock.Controller, mockAwsProvider *boxaws.MockProvider, mockResult []codebuildtypes.BuildBatch) {
+	mockListBuildBatchesPaginator := boxaws.NewMockCodeBuildListBuildBatchesPaginator(ctrl)
+	mockAwsProvider.MockCodeBuildPaginators.EXPECT().NewListBuildBatchesPaginator(&codebuild.ListBuildBatchesInput{
+		Filter: &codebuildtypes.BuildBatchFilter{
+			Status: codebuildtypes.StatusTypeInProgress,
+		},
+	}).Return(mockListBuildBatchesPaginator)
+
+	buildBatchIds := make([]string, 0, len(mockResult))
+	for _, buildBatch := range mockResult {
+		buildBatchIds = append(buildBatchIds, *buildBatch.Id)
+	}
+
+	mockListBuildBatchesPaginator.EXPECT().HasMorePages().Return(true)
+	mockListBuildBatchesPaginator.EXPECT().NextPage(gomock.Any()).Return(&codebuild.ListBuildBatchesOutput{
+		Ids: buildBatchIds,
+	}, nil)
+	mockListBuildBatchesPaginator.EXPECT().HasMorePages().Return(false)
+
+	mockAwsProvider.MockCodeBuildClient.EXPECT().BatchGetBuildBatches(gomock.Any(), &codebuild.BatchGetBuildBatchesInput{
+		Ids: buildBatchIds,
+	}).Return(&codebuild.BatchGetBuildBatchesOutput{
+		BuildBatches: mockResult,
+	}, nil)
+}
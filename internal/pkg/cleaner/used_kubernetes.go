@@ -0,0 +1,196 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	boxkubernetes "github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/kubernetes"
+	gerrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+)
+
+const kubernetesListLimit = 500
+
+// getKubernetesUsedImages collects images of pods and workloads (which can create new pods at any time) in all
+// configured Kubernetes clusters.
+func (u *usedImages) getKubernetesUsedImages(ctx context.Context, imageSet *usedImageSet) error {
+	for _, cluster := range u.kubernetesClusters {
+		err := u.getKubernetesClusterUsedImages(ctx, cluster, imageSet)
+		if err != nil {
+			return gerrors.Wrapf(err, "error getting images used in Kubernetes cluster %v", cluster.Name)
+		}
+	}
+	return nil
+}
+
+func (u *usedImages) getKubernetesClusterUsedImages(ctx context.Context, cluster boxkubernetes.Cluster, imageSet *usedImageSet) error {
+	client := cluster.Client
+
+	err := listAllKubernetesPages(func(options metav1.ListOptions) (string, error) {
+		pods, err := client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, options)
+		if err != nil {
+			return "", gerrors.Wrapf(err, "cannot list pods")
+		}
+		for _, pod := range pods.Items {
+			addKubernetesPodSpecImages(cluster, "Pod", pod.ObjectMeta, pod.Spec, imageSet)
+			addKubernetesPodStatusImages(cluster, pod, imageSet)
+		}
+		return pods.Continue, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = listAllKubernetesPages(func(options metav1.ListOptions) (string, error) {
+		deployments, err := client.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, options)
+		if err != nil {
+			return "", gerrors.Wrapf(err, "cannot list deployments")
+		}
+		for _, deployment := range deployments.Items {
+			addKubernetesPodSpecImages(cluster, "Deployment", deployment.ObjectMeta, deployment.Spec.Template.Spec, imageSet)
+		}
+		return deployments.Continue, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = listAllKubernetesPages(func(options metav1.ListOptions) (string, error) {
+		statefulSets, err := client.AppsV1().StatefulSets(metav1.NamespaceAll).List(ctx, options)
+		if err != nil {
+			return "", gerrors.Wrapf(err, "cannot list stateful sets")
+		}
+		for _, statefulSet := range statefulSets.Items {
+			addKubernetesPodSpecImages(cluster, "StatefulSet", statefulSet.ObjectMeta, statefulSet.Spec.Template.Spec, imageSet)
+		}
+		return statefulSets.Continue, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = listAllKubernetesPages(func(options metav1.ListOptions) (string, error) {
+		daemonSets, err := client.AppsV1().DaemonSets(metav1.NamespaceAll).List(ctx, options)
+		if err != nil {
+			return "", gerrors.Wrapf(err, "cannot list daemon sets")
+		}
+		for _, daemonSet := range daemonSets.Items {
+			addKubernetesPodSpecImages(cluster, "DaemonSet", daemonSet.ObjectMeta, daemonSet.Spec.Template.Spec, imageSet)
+		}
+		return daemonSets.Continue, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = listAllKubernetesPages(func(options metav1.ListOptions) (string, error) {
+		jobs, err := client.BatchV1().Jobs(metav1.NamespaceAll).List(ctx, options)
+		if err != nil {
+			return "", gerrors.Wrapf(err, "cannot list jobs")
+		}
+		for _, job := range jobs.Items {
+			addKubernetesPodSpecImages(cluster, "Job", job.ObjectMeta, job.Spec.Template.Spec, imageSet)
+		}
+		return jobs.Continue, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = listAllKubernetesPages(func(options metav1.ListOptions) (string, error) {
+		cronJobs, err := client.BatchV1().CronJobs(metav1.NamespaceAll).List(ctx, options)
+		if err != nil {
+			return "", gerrors.Wrapf(err, "cannot list cron jobs")
+		}
+		for _, cronJob := range cronJobs.Items {
+			addKubernetesPodSpecImages(cluster, "CronJob", cronJob.ObjectMeta, cronJob.Spec.JobTemplate.Spec.Template.Spec, imageSet)
+		}
+		return cronJobs.Continue, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// listAllKubernetesPages calls list until it stops returning a continue token.
+func listAllKubernetesPages(list func(options metav1.ListOptions) (string, error)) error {
+	options := metav1.ListOptions{
+		Limit: kubernetesListLimit,
+	}
+	for {
+		continueToken, err := list(options)
+		if err != nil {
+			return err
+		}
+		if continueToken == "" {
+			return nil
+		}
+		options.Continue = continueToken
+	}
+}
+
+func addKubernetesPodSpecImages(
+	cluster boxkubernetes.Cluster,
+	kind string,
+	objectMeta metav1.ObjectMeta,
+	podSpec corev1.PodSpec,
+	imageSet *usedImageSet,
+) {
+	images := make([]string, 0, len(podSpec.InitContainers)+len(podSpec.Containers)+len(podSpec.EphemeralContainers))
+	for _, container := range podSpec.InitContainers {
+		images = append(images, container.Image)
+	}
+	for _, container := range podSpec.Containers {
+		images = append(images, container.Image)
+	}
+	for _, container := range podSpec.EphemeralContainers {
+		images = append(images, container.Image)
+	}
+
+	for _, image := range images {
+		if image == "" {
+			continue
+		}
+
+		logger.Debug("Found image used by Kubernetes",
+			"image", image, "kubernetesCluster", cluster.Name, "kind", kind,
+			"name", fmt.Sprintf("%v/%v", objectMeta.Namespace, objectMeta.Name))
+
+		imageSet.add(image)
+	}
+}
+
+// addKubernetesPodStatusImages collects image digests the pod containers are actually running.
+func addKubernetesPodStatusImages(cluster boxkubernetes.Cluster, pod corev1.Pod, imageSet *usedImageSet) {
+	containerStatuses := make([]corev1.ContainerStatus, 0,
+		len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses)+len(pod.Status.EphemeralContainerStatuses))
+	containerStatuses = append(containerStatuses, pod.Status.InitContainerStatuses...)
+	containerStatuses = append(containerStatuses, pod.Status.ContainerStatuses...)
+	containerStatuses = append(containerStatuses, pod.Status.EphemeralContainerStatuses...)
+
+	for _, containerStatus := range containerStatuses {
+		imageId := getKubernetesImageId(containerStatus.ImageID)
+		if imageId == "" {
+			continue
+		}
+
+		logger.Debug("Found image digest used by Kubernetes",
+			"image", imageId, "kubernetesCluster", cluster.Name, "kind", "Pod",
+			"name", fmt.Sprintf("%v/%v", pod.Namespace, pod.Name))
+
+		imageSet.add(imageId)
+	}
+}
+
+// getKubernetesImageId converts a container status image id to the repository@digest form, it returns an empty string
+// if the image id does not contain a repository digest (e.g. only a local image id reported by the container runtime).
+func getKubernetesImageId(imageId string) string {
+	imageId = strings.TrimPrefix(imageId, "docker-pullable://")
+	if !strings.Contains(imageId, "@") || strings.Contains(imageId, "://") {
+		return ""
+	}
+	return imageId
+}
@@ -0,0 +1,202 @@
+package cleaner
+
+import (
+	"context"
+	boxaws "github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/aws"
+	boxkubernetes "github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/kubernetes"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestGetKubernetesUsedImages(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockAwsProvider := boxaws.NewMockProvider(ctrl)
+
+	mockSsm(ctrl, mockAwsProvider, [][]string{}, true)
+	mockEcs(ctrl, mockAwsProvider, []map[string][]map[string]string{})
+	mockLambda(ctrl, mockAwsProvider, []map[string]lambdaMockResult{})
+
+	cluster1 := fake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "namespace1", Name: "pod1"},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{
+					{Image: "init1:v1"},
+				},
+				Containers: []corev1.Container{
+					{Image: "pod1:v1"},
+					{Image: "duplicatedImage1:v1"},
+				},
+				EphemeralContainers: []corev1.EphemeralContainer{
+					{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Image: "debug1:v1"}},
+				},
+			},
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{
+					{ImageID: "docker-pullable://init1@sha256:init1v1"},
+				},
+				ContainerStatuses: []corev1.ContainerStatus{
+					{ImageID: "pod1@sha256:pod1v1"},
+					{ImageID: "docker://sha256:localImageId"},
+				},
+			},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "namespace1", Name: "deployment1"},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{Image: "duplicatedImage1:v1"},
+						},
+					},
+				},
+			},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "namespace2", Name: "statefulSet1"},
+			Spec: appsv1.StatefulSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{Image: "statefulSet1:v1"},
+						},
+					},
+				},
+			},
+		},
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "daemonSet1"},
+			Spec: appsv1.DaemonSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						InitContainers: []corev1.Container{
+							{Image: "daemonSetInit1:v1"},
+						},
+						Containers: []corev1.Container{
+							{Image: "daemonSet1:v1"},
+						},
+					},
+				},
+			},
+		},
+	)
+
+	cluster2 := fake.NewSimpleClientset(
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "namespace1", Name: "job1"},
+			Spec: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{Image: "job1:v1"},
+						},
+					},
+				},
+			},
+		},
+		&batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "namespace1", Name: "cronJob1"},
+			Spec: batchv1.CronJobSpec{
+				JobTemplate: batchv1.JobTemplateSpec{
+					Spec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{Image: "cronJob1:v1"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	)
+
+	expectedImages := map[string]struct{}{
+		"init1:v1":             {},
+		"init1@sha256:init1v1": {},
+		"pod1:v1":              {},
+		"pod1@sha256:pod1v1":   {},
+		"debug1:v1":            {},
+		"duplicatedImage1:v1":  {},
+		"statefulSet1:v1":      {},
+		"daemonSetInit1:v1":    {},
+		"daemonSet1:v1":        {},
+		"job1:v1":              {},
+		"cronJob1:v1":          {},
+	}
+
+	images, err := (&usedImages{
+		awsProvider: mockAwsProvider.Provider,
+		kubernetesClusters: []boxkubernetes.Cluster{
+			{
+				Name:   "cluster1",
+				Client: cluster1,
+			},
+			{
+				Name:   "cluster2",
+				Client: cluster2,
+			},
+		},
+	}).getImages(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := cmp.Diff(
+		expectedImages,
+		images,
+	)
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestGetKubernetesImageId(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		imageId  string
+		expected string
+	}{
+		"Repository digest": {
+			imageId:  "123456789012.dkr.ecr.eu-west-1.amazonaws.com/repo1@sha256:abc",
+			expected: "123456789012.dkr.ecr.eu-west-1.amazonaws.com/repo1@sha256:abc",
+		},
+		"Docker pullable repository digest": {
+			imageId:  "docker-pullable://123456789012.dkr.ecr.eu-west-1.amazonaws.com/repo1@sha256:abc",
+			expected: "123456789012.dkr.ecr.eu-west-1.amazonaws.com/repo1@sha256:abc",
+		},
+		"Local image id": {
+			imageId:  "docker://sha256:abc",
+			expected: "",
+		},
+		"Container not started": {
+			imageId:  "",
+			expected: "",
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := getKubernetesImageId(testCase.imageId)
+
+			if result != testCase.expected {
+				t.Errorf("Result %v different than expected %v", result, testCase.expected)
+			}
+		})
+	}
+}
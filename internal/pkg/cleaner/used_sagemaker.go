@@ -0,0 +1,193 @@
+package cleaner
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/sagemaker"
+	sagemakertypes "github.com/aws/aws-sdk-go-v2/service/sagemaker/types"
+	gerrors "github.com/pkg/errors"
+)
+
+func (u *usedImages) getSageMakerUsedImages(ctx context.Context, imageSet *usedImageSet) error {
+	err := u.getSageMakerModelsUsedImages(ctx, imageSet)
+	if err != nil {
+		return gerrors.Wrapf(err, "error getting images used by SageMaker models")
+	}
+
+	err = u.getSageMakerEndpointsUsedImages(ctx, imageSet)
+	if err != nil {
+		return gerrors.Wrapf(err, "error getting images used by SageMaker endpoints")
+	}
+
+	err = u.getSageMakerTrainingJobsUsedImages(ctx, imageSet)
+	if err != nil {
+		return gerrors.Wrapf(err, "error getting images used by SageMaker training jobs")
+	}
+
+	err = u.getSageMakerProcessingJobsUsedImages(ctx, imageSet)
+	if err != nil {
+		return gerrors.Wrapf(err, "error getting images used by SageMaker processing jobs")
+	}
+
+	return nil
+}
+
+func (u *usedImages) getSageMakerModelsUsedImages(ctx context.Context, imageSet *usedImageSet) error {
+	sageMakerPaginators := u.awsProvider.SageMakerPaginators
+	sageMakerClient := u.awsProvider.SageMakerClient
+
+	listModelsPaginator := sageMakerPaginators.NewListModelsPaginator(&sagemaker.ListModelsInput{})
+	for listModelsPaginator.HasMorePages() {
+		page, err := listModelsPaginator.NextPage(ctx)
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot get list SageMaker models page")
+		}
+
+		for _, model := range page.Models {
+			describeModelOutput, err := sageMakerClient.DescribeModel(ctx, &sagemaker.DescribeModelInput{
+				ModelName: model.ModelName,
+			})
+			if err != nil {
+				return gerrors.Wrapf(err, "cannot describe SageMaker model %v", *model.ModelName)
+			}
+
+			var images []*string
+			if describeModelOutput.PrimaryContainer != nil {
+				images = append(images, describeModelOutput.PrimaryContainer.Image)
+			}
+			for _, container := range describeModelOutput.Containers {
+				images = append(images, container.Image)
+			}
+
+			addSageMakerImages(images, "sageMakerModel", *model.ModelName, imageSet)
+		}
+	}
+
+	return nil
+}
+
+// getSageMakerEndpointsUsedImages collects images deployed to endpoints, they are reported even if the model of the
+// endpoint config has already been deleted.
+func (u *usedImages) getSageMakerEndpointsUsedImages(ctx context.Context, imageSet *usedImageSet) error {
+	sageMakerPaginators := u.awsProvider.SageMakerPaginators
+	sageMakerClient := u.awsProvider.SageMakerClient
+
+	listEndpointsPaginator := sageMakerPaginators.NewListEndpointsPaginator(&sagemaker.ListEndpointsInput{})
+	for listEndpointsPaginator.HasMorePages() {
+		page, err := listEndpointsPaginator.NextPage(ctx)
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot get list SageMaker endpoints page")
+		}
+
+		for _, endpoint := range page.Endpoints {
+			describeEndpointOutput, err := sageMakerClient.DescribeEndpoint(ctx, &sagemaker.DescribeEndpointInput{
+				EndpointName: endpoint.EndpointName,
+			})
+			if err != nil {
+				return gerrors.Wrapf(err, "cannot describe SageMaker endpoint %v", *endpoint.EndpointName)
+			}
+
+			var deployedImages []sagemakertypes.DeployedImage
+			for _, productionVariant := range describeEndpointOutput.ProductionVariants {
+				deployedImages = append(deployedImages, productionVariant.DeployedImages...)
+			}
+			for _, productionVariant := range describeEndpointOutput.ShadowProductionVariants {
+				deployedImages = append(deployedImages, productionVariant.DeployedImages...)
+			}
+			if describeEndpointOutput.PendingDeploymentSummary != nil {
+				for _, productionVariant := range describeEndpointOutput.PendingDeploymentSummary.ProductionVariants {
+					deployedImages = append(deployedImages, productionVariant.DeployedImages...)
+				}
+				for _, productionVariant := range describeEndpointOutput.PendingDeploymentSummary.ShadowProductionVariants {
+					deployedImages = append(deployedImages, productionVariant.DeployedImages...)
+				}
+			}
+
+			images := make([]*string, 0, 2*len(deployedImages))
+			for _, deployedImage := range deployedImages {
+				images = append(images, deployedImage.SpecifiedImage, deployedImage.ResolvedImage)
+			}
+
+			addSageMakerImages(images, "sageMakerEndpoint", *endpoint.EndpointName, imageSet)
+		}
+	}
+
+	return nil
+}
+
+func (u *usedImages) getSageMakerTrainingJobsUsedImages(ctx context.Context, imageSet *usedImageSet) error {
+	sageMakerPaginators := u.awsProvider.SageMakerPaginators
+	sageMakerClient := u.awsProvider.SageMakerClient
+
+	listTrainingJobsPaginator := sageMakerPaginators.NewListTrainingJobsPaginator(&sagemaker.ListTrainingJobsInput{
+		StatusEquals: sagemakertypes.TrainingJobStatusInProgress,
+	})
+	for listTrainingJobsPaginator.HasMorePages() {
+		page, err := listTrainingJobsPaginator.NextPage(ctx)
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot get list SageMaker training jobs page")
+		}
+
+		for _, trainingJob := range page.TrainingJobSummaries {
+			describeTrainingJobOutput, err := sageMakerClient.DescribeTrainingJob(ctx, &sagemaker.DescribeTrainingJobInput{
+				TrainingJobName: trainingJob.TrainingJobName,
+			})
+			if err != nil {
+				return gerrors.Wrapf(err, "cannot describe SageMaker training job %v", *trainingJob.TrainingJobName)
+			}
+
+			var images []*string
+			if describeTrainingJobOutput.AlgorithmSpecification != nil {
+				images = append(images, describeTrainingJobOutput.AlgorithmSpecification.TrainingImage)
+			}
+
+			addSageMakerImages(images, "sageMakerTrainingJob", *trainingJob.TrainingJobName, imageSet)
+		}
+	}
+
+	return nil
+}
+
+func (u *usedImages) getSageMakerProcessingJobsUsedImages(ctx context.Context, imageSet *usedImageSet) error {
+	sageMakerPaginators := u.awsProvider.SageMakerPaginators
+	sageMakerClient := u.awsProvider.SageMakerClient
+
+	listProcessingJobsPaginator := sageMakerPaginators.NewListProcessingJobsPaginator(&sagemaker.ListProcessingJobsInput{
+		StatusEquals: sagemakertypes.ProcessingJobStatusInProgress,
+	})
+	for listProcessingJobsPaginator.HasMorePages() {
+		page, err := listProcessingJobsPaginator.NextPage(ctx)
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot get list SageMaker processing jobs page")
+		}
+
+		for _, processingJob := range page.ProcessingJobSummaries {
+			describeProcessingJobOutput, err := sageMakerClient.DescribeProcessingJob(ctx, &sagemaker.DescribeProcessingJobInput{
+				ProcessingJobName: processingJob.ProcessingJobName,
+			})
+			if err != nil {
+				return gerrors.Wrapf(err, "cannot describe SageMaker processing job %v", *processingJob.ProcessingJobName)
+			}
+
+			var images []*string
+			if describeProcessingJobOutput.AppSpecification != nil {
+				images = append(images, describeProcessingJobOutput.AppSpecification.ImageUri)
+			}
+
+			addSageMakerImages(images, "sageMakerProcessingJob", *processingJob.ProcessingJobName, imageSet)
+		}
+	}
+
+	return nil
+}
+
+func addSageMakerImages(images []*string, resourceKey string, resourceName string, imageSet *usedImageSet) {
+	for _, image := range images {
+		if image == nil || *image == "" {
+			continue
+		}
+
+		logger.Debug("Found image used by SageMaker", "image", *image, resourceKey, resourceName)
+
+		imageSet.add(*image)
+	}
+}
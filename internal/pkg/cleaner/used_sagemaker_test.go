@@ -0,0 +1,230 @@
+package cleaner
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sagemaker"
+	sagemakertypes "github.com/aws/aws-sdk-go-v2/service/sagemaker/types"
+	boxaws "github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+)
+
+func TestGetSageMakerUsedImages(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockAwsProvider := boxaws.NewMockProvider(ctrl)
+
+	mockSsm(ctrl, mockAwsProvider, [][]string{}, true)
+	mockEcs(ctrl, mockAwsProvider, []map[string][]map[string]string{})
+	mockLambda(ctrl, mockAwsProvider, []map[string]lambdaMockResult{})
+
+	mockSageMakerModels(ctrl, mockAwsProvider, []map[string]*sagemaker.DescribeModelOutput{
+		{
+			"model1": {
+				PrimaryContainer: &sagemakertypes.ContainerDefinition{
+					Image: aws.String("model1:v1"),
+				},
+			},
+		},
+		{
+			"model2": {
+				Containers: []sagemakertypes.ContainerDefinition{
+					{
+						Image: aws.String("model2Preprocessing:v1"),
+					},
+					{
+						Image: aws.String("model2Inference:v1"),
+					},
+				},
+			},
+			"model3": {
+				PrimaryContainer: &sagemakertypes.ContainerDefinition{
+					ModelPackageName: aws.String("modelPackage3"),
+				},
+			},
+		},
+	})
+
+	mockSageMakerEndpoints(ctrl, mockAwsProvider, []map[string]*sagemaker.DescribeEndpointOutput{
+		{
+			"endpoint1": {
+				ProductionVariants: []sagemakertypes.ProductionVariantSummary{
+					{
+						DeployedImages: []sagemakertypes.DeployedImage{
+							{
+								SpecifiedImage: aws.String("endpoint1:v1"),
+								ResolvedImage:  aws.String("endpoint1@sha256:endpoint1v1"),
+							},
+						},
+					},
+				},
+				PendingDeploymentSummary: &sagemakertypes.PendingDeploymentSummary{
+					ProductionVariants: []sagemakertypes.PendingProductionVariantSummary{
+						{
+							DeployedImages: []sagemakertypes.DeployedImage{
+								{
+									SpecifiedImage: aws.String("endpoint1:v2"),
+								},
+							},
+						},
+					},
+				},
+			},
+			"endpoint2": {
+				ShadowProductionVariants: []sagemakertypes.ProductionVariantSummary{
+					{
+						DeployedImages: []sagemakertypes.DeployedImage{
+							{
+								SpecifiedImage: aws.String("model1:v1"),
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	mockSageMakerTrainingJobs(ctrl, mockAwsProvider, map[string]string{
+		"trainingJob1": "trainingJob1:v1",
+	})
+
+	mockSageMakerProcessingJobs(ctrl, mockAwsProvider, map[string]string{
+		"processingJob1": "processingJob1:v1",
+	})
+
+	expectedImages := map[string]struct{}{
+		"model1:v1":                    {},
+		"model2Preprocessing:v1":       {},
+		"model2Inference:v1":           {},
+		"endpoint1:v1":                 {},
+		"endpoint1@sha256:endpoint1v1": {},
+		"endpoint1:v2":                 {},
+		"trainingJob1:v1":              {},
+		"processingJob1:v1":            {},
+	}
+
+	images, err := (&usedImages{
+		awsProvider: mockAwsProvider.Provider,
+		config: Config{
+			SageMakerEnabled: true,
+		},
+	}).getImages(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := cmp.Diff(
+		expectedImages,
+		images,
+	)
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func mockSageMakerModels(ctrl *gomock.Controller, mockAwsProvider *boxaws.MockProvider, mockResult []map[string]*sagemaker.DescribeModelOutput) {
+	mockListModelsPaginator := boxaws.NewMockSageMakerListModelsPaginator(ctrl)
+	mockAwsProvider.MockSageMakerPaginators.EXPECT().NewListModelsPaginator(gomock.Any()).Return(mockListModelsPaginator)
+
+	for _, modelsPage := range mockResult {
+		models := make([]sagemakertypes.ModelSummary, 0, len(modelsPage))
+		for modelName, describeModelOutput := range modelsPage {
+			models = append(models, sagemakertypes.ModelSummary{
+				ModelName: aws.String(modelName),
+			})
+
+			mockAwsProvider.MockSageMakerClient.EXPECT().DescribeModel(gomock.Any(), &sagemaker.DescribeModelInput{
+				ModelName: aws.String(modelName),
+			}).Return(describeModelOutput, nil)
+		}
+
+		mockListModelsPaginator.EXPECT().HasMorePages().Return(true)
+		mockListModelsPaginator.EXPECT().NextPage(gomock.Any()).Return(&sagemaker.ListModelsOutput{
+			Models: models,
+		}, nil)
+	}
+	mockListModelsPaginator.EXPECT().HasMorePages().Return(false)
+}
+
+func mockSageMakerEndpoints(ctrl *gomock.Controller, mockAwsProvider *boxaws.MockProvider, mockResult []map[string]*sagemaker.DescribeEndpointOutput) {
+	mockListEndpointsPaginator := boxaws.NewMockSageMakerListEndpointsPaginator(ctrl)
+	mockAwsProvider.MockSageMakerPaginators.EXPECT().NewListEndpointsPaginator(gomock.Any()).Return(mockListEndpointsPaginator)
+
+	for _, endpointsPage := range mockResult {
+		endpoints := make([]sagemakertypes.EndpointSummary, 0, len(endpointsPage))
+		for endpointName, describeEndpointOutput := range endpointsPage {
+			endpoints = append(endpoints, sagemakertypes.EndpointSummary{
+				EndpointName: aws.String(endpointName),
+			})
+
+			mockAwsProvider.MockSageMakerClient.EXPECT().DescribeEndpoint(gomock.Any(), &sagemaker.DescribeEndpointInput{
+				EndpointName: aws.String(endpointName),
+			}).Return(describeEndpointOutput, nil)
+		}
+
+		mockListEndpointsPaginator.EXPECT().HasMorePages().Return(true)
+		mockListEndpointsPaginator.EXPECT().NextPage(gomock.Any()).Return(&sagemaker.ListEndpointsOutput{
+			Endpoints: endpoints,
+		}, nil)
+	}
+	mockListEndpointsPaginator.EXPECT().HasMorePages().Return(false)
+}
+
+func mockSageMakerTrainingJobs(ctrl *gomock.Controller, mockAwsProvider *boxaws.MockProvider, mockResult map[string]string) {
+	mockListTrainingJobsPaginator := boxaws.NewMockSageMakerListTrainingJobsPaginator(ctrl)
+	mockAwsProvider.MockSageMakerPaginators.EXPECT().NewListTrainingJobsPaginator(&sagemaker.ListTrainingJobsInput{
+		StatusEquals: sagemakertypes.TrainingJobStatusInProgress,
+	}).Return(mockListTrainingJobsPaginator)
+
+	trainingJobs := make([]sagemakertypes.TrainingJobSummary, 0, len(mockResult))
+	for trainingJobName, image := range mockResult {
+		trainingJobs = append(trainingJobs, sagemakertypes.TrainingJobSummary{
+			TrainingJobName: aws.String(trainingJobName),
+		})
+
+		mockAwsProvider.MockSageMakerClient.EXPECT().DescribeTrainingJob(gomock.Any(), &sagemaker.DescribeTrainingJobInput{
+			TrainingJobName: aws.String(trainingJobName),
+		}).Return(&sagemaker.DescribeTrainingJobOutput{
+			AlgorithmSpecification: &sagemakertypes.AlgorithmSpecification{
+				TrainingImage: aws.String(image),
+			},
+		}, nil)
+	}
+
+	mockListTrainingJobsPaginator.EXPECT().HasMorePages().Return(true)
+	mockListTrainingJobsPaginator.EXPECT().NextPage(gomock.Any()).Return(&sagemaker.ListTrainingJobsOutput{
+		TrainingJobSummaries: trainingJobs,
+	}, nil)
+	mockListTrainingJobsPaginator.EXPECT().HasMorePages().Return(false)
+}
+
+func mockSageMakerProcessingJobs(ctrl *gomock.Controller, mockAwsProvider *boxaws.MockProvider, mockResult map[string]string) {
+	mockListProcessingJobsPaginator := boxaws.NewMockSageMakerListProcessingJobsPaginator(ctrl)
+	mockAwsProvider.MockSageMakerPaginators.EXPECT().NewListProcessingJobsPaginator(&sagemaker.ListProcessingJobsInput{
+		StatusEquals: sagemakertypes.ProcessingJobStatusInProgress,
+	}).Return(mockListProcessingJobsPaginator)
+
+	processingJobs := make([]sagemakertypes.ProcessingJobSummary, 0, len(mockResult))
+	for processingJobName, image := range mockResult {
+		processingJobs = append(processingJobs, sagemakertypes.ProcessingJobSummary{
+			ProcessingJobName: aws.String(processingJobName),
+		})
+
+		mockAwsProvider.MockSageMakerClient.EXPECT().DescribeProcessingJob(gomock.Any(), &sagemaker.DescribeProcessingJobInput{
+			ProcessingJobName: aws.String(processingJobName),
+		}).Return(&sagemaker.DescribeProcessingJobOutput{
+			AppSpecification: &sagemakertypes.AppSpecification{
+				ImageUri: aws.String(image),
+			},
+		}, nil)
+	}
+
+	mockListProcessingJobsPaginator.EXPECT().HasMorePages().Return(true)
+	mockListProcessingJobsPaginator.EXPECT().NextPage(gomock.Any()).Return(&sagemaker.ListProcessingJobsOutput{
+		ProcessingJobSummaries: processingJobs,
+	}, nil)
+	mockListProcessingJobsPaginator.EXPECT().HasMorePages().Return(false)
+}
@@ -0,0 +1,131 @@
+package cleaner
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	gerrors "github.com/pkg/errors"
+)
+
+// getScheduledTasksUsedImages collects images of ECS tasks started on a schedule, which are usually not running when
+// the cleaner runs, so they are neither visible as services nor as running tasks.
+func (u *usedImages) getScheduledTasksUsedImages(ctx context.Context, imageSet *usedImageSet) error {
+	err := u.getEventBridgeRulesUsedImages(ctx, imageSet)
+	if err != nil {
+		return gerrors.Wrapf(err, "error getting images used by EventBridge rules")
+	}
+
+	err = u.getSchedulerSchedulesUsedImages(ctx, imageSet)
+	if err != nil {
+		return gerrors.Wrapf(err, "error getting images used by EventBridge Scheduler schedules")
+	}
+
+	return nil
+}
+
+func (u *usedImages) getEventBridgeRulesUsedImages(ctx context.Context, imageSet *usedImageSet) error {
+	eventBridgePaginators := u.awsProvider.EventBridgePaginators
+
+	listRulesPaginator := eventBridgePaginators.NewListRulesPaginator(&eventbridge.ListRulesInput{})
+	for listRulesPaginator.HasMorePages() {
+		listRulesPage, err := listRulesPaginator.NextPage(ctx)
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot get list EventBridge rules page")
+		}
+
+		for _, rule := range listRulesPage.Rules {
+
+			listTargetsByRulePaginator := eventBridgePaginators.NewListTargetsByRulePaginator(&eventbridge.ListTargetsByRuleInput{
+				Rule:         rule.Name,
+				EventBusName: rule.EventBusName,
+			})
+			for listTargetsByRulePaginator.HasMorePages() {
+				listTargetsByRulePage, err := listTargetsByRulePaginator.NextPage(ctx)
+				if err != nil {
+					return gerrors.Wrapf(err, "cannot get list EventBridge targets by rule page")
+				}
+
+				for _, target := range listTargetsByRulePage.Targets {
+					if target.EcsParameters == nil || target.EcsParameters.TaskDefinitionArn == nil {
+						continue
+					}
+					taskDefinition := *target.EcsParameters.TaskDefinitionArn
+
+					images, err := u.getEcsTaskDefinitionImages(ctx, taskDefinition)
+					if err != nil {
+						return gerrors.Wrapf(err, "cannot get images of EventBridge rule %v", *rule.Name)
+					}
+
+					for _, image := range images {
+						logger.Debug("Found image used by EventBridge rule",
+							"image", image, "eventBridgeRule", *rule.Name, "taskDefinition", taskDefinition)
+
+						imageSet.add(image)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (u *usedImages) getSchedulerSchedulesUsedImages(ctx context.Context, imageSet *usedImageSet) error {
+	schedulerPaginators := u.awsProvider.SchedulerPaginators
+	schedulerClient := u.awsProvider.SchedulerClient
+
+	listSchedulesPaginator := schedulerPaginators.NewListSchedulesPaginator(&scheduler.ListSchedulesInput{})
+	for listSchedulesPaginator.HasMorePages() {
+		listSchedulesPage, err := listSchedulesPaginator.NextPage(ctx)
+		if err != nil {
+			return gerrors.Wrapf(err, "cannot get list EventBridge Scheduler schedules page")
+		}
+
+		for _, schedule := range listSchedulesPage.Schedules {
+			// schedule summaries contain only the target ARN, so the schedule is described only for ECS targets
+			if schedule.Target == nil || !isEcsArn(schedule.Target.Arn) {
+				continue
+			}
+
+			getScheduleOutput, err := schedulerClient.GetSchedule(ctx, &scheduler.GetScheduleInput{
+				Name:      schedule.Name,
+				GroupName: schedule.GroupName,
+			})
+			if err != nil {
+				return gerrors.Wrapf(err, "cannot get EventBridge Scheduler schedule %v", *schedule.Name)
+			}
+
+			target := getScheduleOutput.Target
+			if target == nil || target.EcsParameters == nil || target.EcsParameters.TaskDefinitionArn == nil {
+				continue
+			}
+			taskDefinition := *target.EcsParameters.TaskDefinitionArn
+
+			images, err := u.getEcsTaskDefinitionImages(ctx, taskDefinition)
+			if err != nil {
+				return gerrors.Wrapf(err, "cannot get images of EventBridge Scheduler schedule %v", *schedule.Name)
+			}
+
+			for _, image := range images {
+				logger.Debug("Found image used by EventBridge Scheduler schedule",
+					"image", image, "schedule", *schedule.Name, "taskDefinition", taskDefinition)
+
+				imageSet.add(image)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isEcsArn(resourceArn *string) bool {
+	if resourceArn == nil {
+		return false
+	}
+	parsedArn, err := arn.Parse(*resourceArn)
+	if err != nil {
+		return false
+	}
+	return parsedArn.Service == "ecs"
+}
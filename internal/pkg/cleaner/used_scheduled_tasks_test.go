@@ -0,0 +1,231 @@
+package cleaner
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	eventbridgetypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	schedulertypes "github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	boxaws "github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+)
+
+func TestGetScheduledTasksUsedImages(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockAwsProvider := boxaws.NewMockProvider(ctrl)
+
+	mockSsm(ctrl, mockAwsProvider, [][]string{}, true)
+	mockEcs(ctrl, mockAwsProvider, []map[string][]map[string]string{})
+	mockLambda(ctrl, mockAwsProvider, []map[string]lambdaMockResult{})
+
+	mockEventBridgeRules(ctrl, mockAwsProvider, [][]eventBridgeRuleMockResult{
+		{
+			{
+				name: "rule1",
+				targets: [][]eventbridgetypes.Target{
+					{
+						{
+							Arn: aws.String("arn:aws:ecs:mock-aws-region:123456789012:cluster/cluster1"),
+							EcsParameters: &eventbridgetypes.EcsParameters{
+								TaskDefinitionArn: aws.String("report1TaskDefinition"),
+							},
+						},
+						{
+							Arn: aws.String("arn:aws:lambda:mock-aws-region:123456789012:function:lambda1"),
+						},
+					},
+				},
+			},
+			{
+				name:    "rule2",
+				targets: [][]eventbridgetypes.Target{},
+			},
+		},
+		{
+			{
+				name: "rule3",
+				targets: [][]eventbridgetypes.Target{
+					{},
+					{
+						{
+							Arn: aws.String("arn:aws:ecs:mock-aws-region:123456789012:cluster/cluster1"),
+							EcsParameters: &eventbridgetypes.EcsParameters{
+								TaskDefinitionArn: aws.String("report2TaskDefinition"),
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	mockSchedulerSchedules(ctrl, mockAwsProvider, [][]schedulerScheduleMockResult{
+		{
+			{
+				name:      "schedule1",
+				groupName: "default",
+				targetArn: "arn:aws:ecs:mock-aws-region:123456789012:cluster/cluster1",
+				ecsParameters: &schedulertypes.EcsParameters{
+					TaskDefinitionArn: aws.String("report3TaskDefinition"),
+				},
+			},
+			{
+				name:      "schedule2",
+				groupName: "group1",
+				targetArn: "arn:aws:sqs:mock-aws-region:123456789012:queue1",
+			},
+		},
+		{
+			{
+				name:      "schedule3",
+				groupName: "group1",
+				targetArn: "arn:aws:ecs:mock-aws-region:123456789012:cluster/cluster1",
+				ecsParameters: &schedulertypes.EcsParameters{
+					TaskDefinitionArn: aws.String("report1TaskDefinition"),
+				},
+			},
+		},
+	})
+
+	for taskDefinition, image := range map[string]string{
+		"report1TaskDefinition": "report1:v1",
+		"report2TaskDefinition": "report2:v1",
+		"report3TaskDefinition": "report3:v1",
+	} {
+		mockAwsProvider.MockEcsClient.EXPECT().DescribeTaskDefinition(gomock.Any(), &ecs.DescribeTaskDefinitionInput{
+			TaskDefinition: aws.String(taskDefinition),
+		}).Return(&ecs.DescribeTaskDefinitionOutput{
+			TaskDefinition: &ecstypes.TaskDefinition{
+				ContainerDefinitions: []ecstypes.ContainerDefinition{
+					{
+						Image: aws.String(image),
+					},
+				},
+			},
+		}, nil).MinTimes(1)
+	}
+
+	expectedImages := map[string]struct{}{
+		"report1:v1": {},
+		"report2:v1": {},
+		"report3:v1": {},
+	}
+
+	images, err := (&usedImages{
+		awsProvider: mockAwsProvider.Provider,
+		config: Config{
+			ScheduledTasksEnabled: true,
+		},
+	}).getImages(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := cmp.Diff(
+		expectedImages,
+		images,
+	)
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+type eventBridgeRuleMockResult struct {
+	name    string
+	targets [][]eventbridgetypes.Target
+}
+
+func mockEventBridgeRules(ctrl *gomock.Controller, mockAwsProvider *boxaws.MockProvider, mockResult [][]eventBridgeRuleMockResult) {
+	mockListRulesPaginator := boxaws.NewMockEventBridgeListRulesPaginator(ctrl)
+	mockAwsProvider.MockEventBridgePaginators.EXPECT().NewListRulesPaginator(gomock.Any()).Return(mockListRulesPaginator)
+
+	for _, listRulesPage := range mockResult {
+		mockListRulesPaginator.EXPECT().HasMorePages().Return(true)
+
+		rules := make([]eventbridgetypes.Rule, 0, len(listRulesPage))
+		for _, rule := range listRulesPage {
+			rules = append(rules, eventbridgetypes.Rule{
+				Name:         aws.String(rule.name),
+				EventBusName: aws.String("default"),
+			})
+		}
+
+		mockListRulesPaginator.EXPECT().NextPage(gomock.Any()).Return(&eventbridge.ListRulesOutput{
+			Rules: rules,
+		}, nil)
+
+		for _, rule := range listRulesPage {
+			mockListTargetsByRulePaginator := boxaws.NewMockEventBridgeListTargetsByRulePaginator(ctrl)
+			mockAwsProvider.MockEventBridgePaginators.EXPECT().NewListTargetsByRulePaginator(&eventbridge.ListTargetsByRuleInput{
+				Rule:         aws.String(rule.name),
+				EventBusName: aws.String("default"),
+			}).Return(mockListTargetsByRulePaginator)
+
+			for _, targetsPage := range rule.targets {
+				mockListTargetsByRulePaginator.EXPECT().HasMorePages().Return(true)
+				mockListTargetsByRulePaginator.EXPECT().NextPage(gomock.Any()).Return(&eventbridge.ListTargetsByRuleOutput{
+					Targets: targetsPage,
+				}, nil)
+			}
+			mockListTargetsByRulePaginator.EXPECT().HasMorePages().Return(false)
+		}
+	}
+	mockListRulesPaginator.EXPECT().HasMorePages().Return(false)
+}
+
+type schedulerScheduleMockResult struct {
+	name          string
+	groupName     string
+	targetArn     string
+	ecsParameters *schedulertypes.EcsParameters
+}
+
+func mockSchedulerSchedules(ctrl *gomock.Controller, mockAwsProvider *boxaws.MockProvider, mockResult [][]schedulerScheduleMockResult) {
+	mockListSchedulesPaginator := boxaws.NewMockSchedulerListSchedulesPaginator(ctrl)
+	mockAwsProvider.MockSchedulerPaginators.EXPECT().NewListSchedulesPaginator(gomock.Any()).Return(mockListSchedulesPaginator)
+
+	for _, listSchedulesPage := range mockResult {
+		mockListSchedulesPaginator.EXPECT().HasMorePages().Return(true)
+
+		schedules := make([]schedulertypes.ScheduleSummary, 0, len(listSchedulesPage))
+		for _, schedule := range listSchedulesPage {
+			schedules = append(schedules, schedulertypes.ScheduleSummary{
+				Name:      aws.String(schedule.name),
+				GroupName: aws.String(schedule.groupName),
+				Target: &schedulertypes.TargetSummary{
+					Arn: aws.String(schedule.targetArn),
+				},
+			})
+		}
+
+		mockListSchedulesPaginator.EXPECT().NextPage(gomock.Any()).Return(&scheduler.ListSchedulesOutput{
+			Schedules: schedules,
+		}, nil)
+
+		for _, schedule := range listSchedulesPage {
+			if !isEcsArn(aws.String(schedule.targetArn)) {
+				continue
+			}
+
+			mockAwsProvider.MockSchedulerClient.EXPECT().GetSchedule(gomock.Any(), &scheduler.GetScheduleInput{
+				Name:      aws.String(schedule.name),
+				GroupName: aws.String(schedule.groupName),
+			}).Return(&scheduler.GetScheduleOutput{
+				Name:      aws.String(schedule.name),
+				GroupName: aws.String(schedule.groupName),
+				Target: &schedulertypes.Target{
+					Arn:           aws.String(schedule.targetArn),
+					EcsParameters: schedule.ecsParameters,
+				},
+			}, nil)
+		}
+	}
+	mockListSchedulesPaginator.EXPECT().HasMorePages().Return(false)
+}
@@ -1,6 +1,7 @@
 package cleaner
 
 import (
+	"context"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/apprunner"
@@ -15,6 +16,7 @@ import (
 	boxaws "github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/aws"
 	"github.com/golang/mock/gomock"
 	"github.com/google/go-cmp/cmp"
+	"sort"
 	"testing"
 )
 
@@ -100,6 +102,10 @@ func TestGetUsedImages(t *testing.T) {
 			"lambda5": {
 				image:       ptr.String("image4:v2"),
 				packageType: lambdatypes.PackageTypeImage,
+				versions: map[string]string{
+					"1": "image4:v0",
+					"2": "image4:v1",
+				},
 			},
 			"lambda6": {
 				image:       ptr.String("duplicatedImage4:v1"),
@@ -132,6 +138,7 @@ func TestGetUsedImages(t *testing.T) {
 		"image4:v1":           {},
 		"duplicatedImage2:v1": {},
 		"image4:v2":           {},
+		"image4:v0":           {},
 		"image5:v1":           {},
 		"duplicatedImage3:v1": {},
 		"image5:v2":           {},
@@ -141,7 +148,7 @@ func TestGetUsedImages(t *testing.T) {
 
 	images, err := (&usedImages{
 		awsProvider: mockAwsProvider.Provider,
-	}).getImages()
+	}).getImages(context.TODO())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -193,7 +200,87 @@ func TestAppRunnerNotAvailable(t *testing.T) {
 
 	images, err := (&usedImages{
 		awsProvider: mockAwsProvider.Provider,
-	}).getImages()
+	}).getImages(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := cmp.Diff(
+		expectedImages,
+		images,
+	)
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestGetUsedImagesInMultipleRegions(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockAwsProvider := boxaws.NewMockProvider(ctrl)
+	mockUsageAwsProvider := boxaws.NewMockProvider(ctrl)
+	mockUsageAwsProvider.Provider.Region = "mock-aws-usage-region"
+
+	mockSsm(ctrl, mockAwsProvider, [][]string{
+		{
+			"mock-aws-region",
+		},
+	}, false)
+	mockEcs(ctrl, mockAwsProvider, []map[string][]map[string]string{
+		{
+			"cluster1": {
+				{
+					"ecsService1": "image1:v1",
+				},
+			},
+		},
+	})
+	mockLambda(ctrl, mockAwsProvider, []map[string]lambdaMockResult{})
+	mockAppRunner(ctrl, mockAwsProvider, []map[string]string{
+		{
+			"appRunnerService1": "image2:v1",
+		},
+	})
+
+	// App Runner is not available in the usage region, so it must not be called there
+	mockSsm(ctrl, mockUsageAwsProvider, [][]string{
+		{
+			"mock-aws-region",
+		},
+	}, true)
+	mockEcs(ctrl, mockUsageAwsProvider, []map[string][]map[string]string{
+		{
+			"cluster1": {
+				{
+					"ecsService1": "image1:v1",
+					"ecsService2": "image3:v1",
+				},
+			},
+		},
+	})
+	mockLambda(ctrl, mockUsageAwsProvider, []map[string]lambdaMockResult{
+		{
+			"lambda1": {
+				image:       ptr.String("image4:v1"),
+				packageType: lambdatypes.PackageTypeImage,
+			},
+		},
+	})
+
+	expectedImages := map[string]struct{}{
+		"image1:v1": {},
+		"image2:v1": {},
+		"image3:v1": {},
+		"image4:v1": {},
+	}
+
+	images, err := (&usedImages{
+		awsProvider: mockAwsProvider.Provider,
+		usageAwsProviders: []*boxaws.Provider{
+			mockUsageAwsProvider.Provider,
+		},
+	}).getImages(context.TODO())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -227,12 +314,397 @@ func TestEcsListServicesEmptyResult(t *testing.T) {
 
 	_, err := (&usedImages{
 		awsProvider: mockAwsProvider.Provider,
-	}).getImages()
+	}).getImages(context.TODO())
 	if err != nil {
 		t.Fatal(err)
 	}
 }
 
+func TestEcsServiceDeployments(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockAwsProvider := boxaws.NewMockProvider(ctrl)
+
+	mockSsm(ctrl, mockAwsProvider, [][]string{}, true)
+
+	mockEcsListClustersPaginator := boxaws.NewMockEcsListClustersPaginator(ctrl)
+	mockAwsProvider.MockEcsPaginators.EXPECT().NewListClustersPaginator(gomock.Any()).Return(mockEcsListClustersPaginator)
+	mockEcsListClustersPaginator.EXPECT().HasMorePages().Return(true)
+	mockEcsListClustersPaginator.EXPECT().NextPage(gomock.Any()).Return(&ecs.ListClustersOutput{
+		ClusterArns: []string{"cluster1Arn"},
+	}, nil)
+	mockEcsListClustersPaginator.EXPECT().HasMorePages().Return(false)
+
+	mockEcsListServicesPaginator := boxaws.NewMockEcsListServicesPaginator(ctrl)
+	mockAwsProvider.MockEcsPaginators.EXPECT().NewListServicesPaginator(&ecs.ListServicesInput{
+		Cluster: aws.String("cluster1Arn"),
+	}).Return(mockEcsListServicesPaginator)
+	mockEcsListServicesPaginator.EXPECT().HasMorePages().Return(true)
+	mockEcsListServicesPaginator.EXPECT().NextPage(gomock.Any()).Return(&ecs.ListServicesOutput{
+		ServiceArns: []string{"ecsService1Arn"},
+	}, nil)
+	mockEcsListServicesPaginator.EXPECT().HasMorePages().Return(false)
+
+	mockEcsTasks(ctrl, mockAwsProvider, "cluster1Arn", [][]ecstypes.Task{})
+
+	mockAwsProvider.MockEcsClient.EXPECT().DescribeServices(gomock.Any(), &ecs.DescribeServicesInput{
+		Services: []string{"ecsService1Arn"},
+		Cluster:  aws.String("cluster1Arn"),
+	}).Return(&ecs.DescribeServicesOutput{
+		Services: []ecstypes.Service{
+			{
+				ServiceName:    aws.String("ecsService1"),
+				TaskDefinition: aws.String("taskDefinition:3"),
+				Deployments: []ecstypes.Deployment{
+					{
+						Status:         aws.String("PRIMARY"),
+						TaskDefinition: aws.String("taskDefinition:3"),
+					},
+					{
+						Status:         aws.String("ACTIVE"),
+						TaskDefinition: aws.String("taskDefinition:2"),
+					},
+				},
+				TaskSets: []ecstypes.TaskSet{
+					{
+						Status:         aws.String("ACTIVE"),
+						TaskDefinition: aws.String("taskDefinition:1"),
+					},
+				},
+			},
+		},
+	}, nil)
+
+	for taskDefinition, image := range map[string]string{
+		"taskDefinition:1": "image1:v1",
+		"taskDefinition:2": "image1:v2",
+		"taskDefinition:3": "image1:v3",
+	} {
+		mockAwsProvider.MockEcsClient.EXPECT().DescribeTaskDefinition(gomock.Any(), &ecs.DescribeTaskDefinitionInput{
+			TaskDefinition: aws.String(taskDefinition),
+		}).Return(&ecs.DescribeTaskDefinitionOutput{
+			TaskDefinition: &ecstypes.TaskDefinition{
+				ContainerDefinitions: []ecstypes.ContainerDefinition{
+					{
+						Image: aws.String(image),
+					},
+				},
+			},
+		}, nil)
+	}
+
+	mockLambda(ctrl, mockAwsProvider, []map[string]lambdaMockResult{})
+
+	expectedImages := map[string]struct{}{
+		"image1:v1": {},
+		"image1:v2": {},
+		"image1:v3": {},
+	}
+
+	images, err := (&usedImages{
+		awsProvider: mockAwsProvider.Provider,
+	}).getImages(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := cmp.Diff(
+		expectedImages,
+		images,
+	)
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestEcsSharedTaskDefinition(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockAwsProvider := boxaws.NewMockProvider(ctrl)
+
+	mockSsm(ctrl, mockAwsProvider, [][]string{}, true)
+
+	clusterArns := []string{"cluster1Arn", "cluster2Arn", "cluster3Arn"}
+
+	mockEcsListClustersPaginator := boxaws.NewMockEcsListClustersPaginator(ctrl)
+	mockAwsProvider.MockEcsPaginators.EXPECT().NewListClustersPaginator(gomock.Any()).Return(mockEcsListClustersPaginator)
+	mockEcsListClustersPaginator.EXPECT().HasMorePages().Return(true)
+	mockEcsListClustersPaginator.EXPECT().NextPage(gomock.Any()).Return(&ecs.ListClustersOutput{
+		ClusterArns: clusterArns,
+	}, nil)
+	mockEcsListClustersPaginator.EXPECT().HasMorePages().Return(false)
+
+	for _, clusterArn := range clusterArns {
+		mockEcsListServicesPaginator := boxaws.NewMockEcsListServicesPaginator(ctrl)
+		mockAwsProvider.MockEcsPaginators.EXPECT().NewListServicesPaginator(&ecs.ListServicesInput{
+			Cluster: aws.String(clusterArn),
+		}).Return(mockEcsListServicesPaginator)
+		mockEcsListServicesPaginator.EXPECT().HasMorePages().Return(true)
+		mockEcsListServicesPaginator.EXPECT().NextPage(gomock.Any()).Return(&ecs.ListServicesOutput{
+			ServiceArns: []string{"ecsService1Arn", "ecsService2Arn"},
+		}, nil)
+		mockEcsListServicesPaginator.EXPECT().HasMorePages().Return(false)
+
+		mockAwsProvider.MockEcsClient.EXPECT().DescribeServices(gomock.Any(), &ecs.DescribeServicesInput{
+			Services: []string{"ecsService1Arn", "ecsService2Arn"},
+			Cluster:  aws.String(clusterArn),
+		}).Return(&ecs.DescribeServicesOutput{
+			Services: []ecstypes.Service{
+				{
+					ServiceName:    aws.String("ecsService1"),
+					TaskDefinition: aws.String("sharedTaskDefinition:1"),
+				},
+				{
+					ServiceName:    aws.String("ecsService2"),
+					TaskDefinition: aws.String(fmt.Sprintf("%vTaskDefinition:1", clusterArn)),
+				},
+			},
+		}, nil)
+
+		mockAwsProvider.MockEcsClient.EXPECT().DescribeTaskDefinition(gomock.Any(), &ecs.DescribeTaskDefinitionInput{
+			TaskDefinition: aws.String(fmt.Sprintf("%vTaskDefinition:1", clusterArn)),
+		}).Return(&ecs.DescribeTaskDefinitionOutput{
+			TaskDefinition: &ecstypes.TaskDefinition{
+				ContainerDefinitions: []ecstypes.ContainerDefinition{
+					{
+						Image: aws.String(fmt.Sprintf("%vImage:v1", clusterArn)),
+					},
+				},
+			},
+		}, nil)
+
+		mockEcsTasks(ctrl, mockAwsProvider, clusterArn, [][]ecstypes.Task{})
+	}
+
+	// the shared task definition is described only once
+	mockAwsProvider.MockEcsClient.EXPECT().DescribeTaskDefinition(gomock.Any(), &ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String("sharedTaskDefinition:1"),
+	}).Return(&ecs.DescribeTaskDefinitionOutput{
+		TaskDefinition: &ecstypes.TaskDefinition{
+			ContainerDefinitions: []ecstypes.ContainerDefinition{
+				{
+					Image: aws.String("sharedImage:v1"),
+				},
+			},
+		},
+	}, nil).Times(1)
+
+	mockLambda(ctrl, mockAwsProvider, []map[string]lambdaMockResult{})
+
+	expectedImages := map[string]struct{}{
+		"sharedImage:v1":      {},
+		"cluster1ArnImage:v1": {},
+		"cluster2ArnImage:v1": {},
+		"cluster3ArnImage:v1": {},
+	}
+
+	images, err := (&usedImages{
+		awsProvider: mockAwsProvider.Provider,
+	}).getImages(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := cmp.Diff(
+		expectedImages,
+		images,
+	)
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestEcsRunningTasks(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockAwsProvider := boxaws.NewMockProvider(ctrl)
+
+	mockSsm(ctrl, mockAwsProvider, [][]string{}, true)
+
+	mockEcsListClustersPaginator := boxaws.NewMockEcsListClustersPaginator(ctrl)
+	mockAwsProvider.MockEcsPaginators.EXPECT().NewListClustersPaginator(gomock.Any()).Return(mockEcsListClustersPaginator)
+	mockEcsListClustersPaginator.EXPECT().HasMorePages().Return(true)
+	mockEcsListClustersPaginator.EXPECT().NextPage(gomock.Any()).Return(&ecs.ListClustersOutput{
+		ClusterArns: []string{"cluster1Arn"},
+	}, nil)
+	mockEcsListClustersPaginator.EXPECT().HasMorePages().Return(false)
+
+	mockEcsListServicesPaginator := boxaws.NewMockEcsListServicesPaginator(ctrl)
+	mockAwsProvider.MockEcsPaginators.EXPECT().NewListServicesPaginator(gomock.Any()).Return(mockEcsListServicesPaginator)
+	mockEcsListServicesPaginator.EXPECT().HasMorePages().Return(false)
+
+	mockEcsTasks(ctrl, mockAwsProvider, "cluster1Arn", [][]ecstypes.Task{
+		{
+			{
+				TaskArn: aws.String("task1Arn"),
+				Containers: []ecstypes.Container{
+					{
+						Image:       aws.String("registry:5000/image1:v1"),
+						ImageDigest: aws.String("sha256:image1v1"),
+					},
+					{
+						Image: aws.String("image2:v1"),
+					},
+				},
+			},
+		},
+		{},
+		{
+			{
+				TaskArn: aws.String("task2Arn"),
+				Containers: []ecstypes.Container{
+					{
+						Image:       aws.String("image3@sha256:image3v1"),
+						ImageDigest: aws.String("sha256:image3v1"),
+					},
+				},
+			},
+		},
+	})
+
+	mockLambda(ctrl, mockAwsProvider, []map[string]lambdaMockResult{})
+
+	expectedImages := map[string]struct{}{
+		"registry:5000/image1:v1":              {},
+		"registry:5000/image1@sha256:image1v1": {},
+		"image2:v1":                            {},
+		"image3@sha256:image3v1":               {},
+	}
+
+	images, err := (&usedImages{
+		awsProvider: mockAwsProvider.Provider,
+	}).getImages(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := cmp.Diff(
+		expectedImages,
+		images,
+	)
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestLambdaReferencedVersionsOnly(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockAwsProvider := boxaws.NewMockProvider(ctrl)
+
+	mockSsm(ctrl, mockAwsProvider, [][]string{}, true)
+	mockEcs(ctrl, mockAwsProvider, []map[string][]map[string]string{})
+
+	mockLambdaListFunctionsPaginator := boxaws.NewMockLambdaListFunctionsPaginator(ctrl)
+	mockAwsProvider.MockLambdaPaginators.EXPECT().NewListFunctionsPaginator(gomock.Any()).Return(mockLambdaListFunctionsPaginator)
+	mockLambdaListFunctionsPaginator.EXPECT().HasMorePages().Return(true)
+	mockLambdaListFunctionsPaginator.EXPECT().NextPage(gomock.Any()).Return(&lambda.ListFunctionsOutput{
+		Functions: []lambdatypes.FunctionConfiguration{
+			{
+				FunctionName: aws.String("lambda1"),
+				FunctionArn:  aws.String("lambda1Arn"),
+				PackageType:  lambdatypes.PackageTypeImage,
+			},
+		},
+	}, nil)
+	mockLambdaListFunctionsPaginator.EXPECT().HasMorePages().Return(false)
+
+	mockLambdaListAliasesPaginator := boxaws.NewMockLambdaListAliasesPaginator(ctrl)
+	mockAwsProvider.MockLambdaPaginators.EXPECT().NewListAliasesPaginator(&lambda.ListAliasesInput{
+		FunctionName: aws.String("lambda1Arn"),
+	}).Return(mockLambdaListAliasesPaginator)
+	mockLambdaListAliasesPaginator.EXPECT().HasMorePages().Return(true)
+	mockLambdaListAliasesPaginator.EXPECT().NextPage(gomock.Any()).Return(&lambda.ListAliasesOutput{
+		Aliases: []lambdatypes.AliasConfiguration{
+			{
+				Name:            aws.String("prod"),
+				FunctionVersion: aws.String("3"),
+				RoutingConfig: &lambdatypes.AliasRoutingConfiguration{
+					AdditionalVersionWeights: map[string]float64{
+						"2": 0.1,
+					},
+				},
+			},
+			{
+				Name:            aws.String("dev"),
+				FunctionVersion: aws.String("$LATEST"),
+			},
+		},
+	}, nil)
+	mockLambdaListAliasesPaginator.EXPECT().HasMorePages().Return(false)
+
+	mockLambdaListProvisionedConcurrencyConfigsPaginator := boxaws.NewMockLambdaListProvisionedConcurrencyConfigsPaginator(ctrl)
+	mockAwsProvider.MockLambdaPaginators.EXPECT().NewListProvisionedConcurrencyConfigsPaginator(&lambda.ListProvisionedConcurrencyConfigsInput{
+		FunctionName: aws.String("lambda1Arn"),
+	}).Return(mockLambdaListProvisionedConcurrencyConfigsPaginator)
+	mockLambdaListProvisionedConcurrencyConfigsPaginator.EXPECT().HasMorePages().Return(true)
+	mockLambdaListProvisionedConcurrencyConfigsPaginator.EXPECT().NextPage(gomock.Any()).Return(&lambda.ListProvisionedConcurrencyConfigsOutput{
+		ProvisionedConcurrencyConfigs: []lambdatypes.ProvisionedConcurrencyConfigListItem{
+			{
+				FunctionArn: aws.String("arn:aws:lambda:mock-aws-region:123456789012:function:lambda1:prod"),
+			},
+			{
+				FunctionArn: aws.String("arn:aws:lambda:mock-aws-region:123456789012:function:lambda1:1"),
+			},
+		},
+	}, nil)
+	mockLambdaListProvisionedConcurrencyConfigsPaginator.EXPECT().HasMorePages().Return(false)
+
+	for qualifier, tag := range map[string]string{
+		"":  "latest",
+		"1": "v1",
+		"2": "v2",
+		"3": "v3",
+	} {
+		var getFunctionQualifier *string
+		if qualifier != "" {
+			getFunctionQualifier = aws.String(qualifier)
+		}
+		mockAwsProvider.MockLambdaClient.EXPECT().GetFunction(gomock.Any(), &lambda.GetFunctionInput{
+			FunctionName: aws.String("lambda1Arn"),
+			Qualifier:    getFunctionQualifier,
+		}).Return(&lambda.GetFunctionOutput{
+			Code: &lambdatypes.FunctionCodeLocation{
+				ImageUri:         aws.String(fmt.Sprintf("image1:%v", tag)),
+				ResolvedImageUri: aws.String(fmt.Sprintf("image1@sha256:%v", tag)),
+			},
+		}, nil)
+	}
+
+	expectedImages := map[string]struct{}{
+		"image1:latest":        {},
+		"image1@sha256:latest": {},
+		"image1:v1":            {},
+		"image1@sha256:v1":     {},
+		"image1:v2":            {},
+		"image1@sha256:v2":     {},
+		"image1:v3":            {},
+		"image1@sha256:v3":     {},
+	}
+
+	images, err := (&usedImages{
+		awsProvider: mockAwsProvider.Provider,
+		config: Config{
+			LambdaReferencedVersionsOnly: true,
+		},
+	}).getImages(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := cmp.Diff(
+		expectedImages,
+		images,
+	)
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
 func mockSsm(
 	ctrl *gomock.Controller,
 	mockAwsProvider *boxaws.MockProvider,
@@ -333,14 +805,48 @@ func mockEcs(ctrl *gomock.Controller, mockAwsProvider *boxaws.MockProvider, mock
 				}
 			}
 			mockEcsListServicesPaginator.EXPECT().HasMorePages().Return(false)
+
+			mockEcsTasks(ctrl, mockAwsProvider, clusterArn, [][]ecstypes.Task{})
 		}
 	}
 	mockEcsListClustersPaginator.EXPECT().HasMorePages().Return(false)
 }
 
+func mockEcsTasks(ctrl *gomock.Controller, mockAwsProvider *boxaws.MockProvider, clusterArn string, mockResult [][]ecstypes.Task) {
+	mockEcsListTasksPaginator := boxaws.NewMockEcsListTasksPaginator(ctrl)
+	mockAwsProvider.MockEcsPaginators.EXPECT().NewListTasksPaginator(&ecs.ListTasksInput{
+		Cluster:       aws.String(clusterArn),
+		DesiredStatus: ecstypes.DesiredStatusRunning,
+	}).Return(mockEcsListTasksPaginator)
+
+	for _, listTasksPage := range mockResult {
+		mockEcsListTasksPaginator.EXPECT().HasMorePages().Return(true)
+
+		taskArns := make([]string, 0, len(listTasksPage))
+		for _, task := range listTasksPage {
+			taskArns = append(taskArns, *task.TaskArn)
+		}
+
+		mockEcsListTasksPaginator.EXPECT().NextPage(gomock.Any()).Return(&ecs.ListTasksOutput{
+			TaskArns: taskArns,
+		}, nil)
+
+		if len(taskArns) > 0 {
+			mockAwsProvider.MockEcsClient.EXPECT().DescribeTasks(gomock.Any(), &ecs.DescribeTasksInput{
+				Tasks:   taskArns,
+				Cluster: aws.String(clusterArn),
+			}).Return(&ecs.DescribeTasksOutput{
+				Tasks: listTasksPage,
+			}, nil)
+		}
+	}
+	mockEcsListTasksPaginator.EXPECT().HasMorePages().Return(false)
+}
+
 type lambdaMockResult struct {
 	image       *string
 	packageType lambdatypes.PackageType
+	versions    map[string]string
 }
 
 func mockLambda(ctrl *gomock.Controller, mockAwsProvider *boxaws.MockProvider, mockResult []map[string]lambdaMockResult) {
@@ -374,12 +880,53 @@ func mockLambda(ctrl *gomock.Controller, mockAwsProvider *boxaws.MockProvider, m
 						ImageUri: functionParameters.image,
 					},
 				}, nil)
+
+				mockLambdaVersions(ctrl, mockAwsProvider, *function.FunctionArn, functionParameters.versions)
 			}
 		}
 	}
 	mockLambdaListFunctionsPaginator.EXPECT().HasMorePages().Return(false)
 }
 
+func mockLambdaVersions(ctrl *gomock.Controller, mockAwsProvider *boxaws.MockProvider, functionArn string, versions map[string]string) {
+	mockLambdaListVersionsByFunctionPaginator := boxaws.NewMockLambdaListVersionsByFunctionPaginator(ctrl)
+	mockAwsProvider.MockLambdaPaginators.EXPECT().NewListVersionsByFunctionPaginator(&lambda.ListVersionsByFunctionInput{
+		FunctionName: aws.String(functionArn),
+	}).Return(mockLambdaListVersionsByFunctionPaginator)
+
+	versionNames := make([]string, 0, len(versions))
+	for version := range versions {
+		versionNames = append(versionNames, version)
+	}
+	sort.Strings(versionNames)
+
+	functionVersions := []lambdatypes.FunctionConfiguration{
+		{
+			Version: aws.String("$LATEST"),
+		},
+	}
+	for _, version := range versionNames {
+		functionVersions = append(functionVersions, lambdatypes.FunctionConfiguration{
+			Version: aws.String(version),
+		})
+
+		mockAwsProvider.MockLambdaClient.EXPECT().GetFunction(gomock.Any(), &lambda.GetFunctionInput{
+			FunctionName: aws.String(functionArn),
+			Qualifier:    aws.String(version),
+		}).Return(&lambda.GetFunctionOutput{
+			Code: &lambdatypes.FunctionCodeLocation{
+				ImageUri: aws.String(versions[version]),
+			},
+		}, nil)
+	}
+
+	mockLambdaListVersionsByFunctionPaginator.EXPECT().HasMorePages().Return(true)
+	mockLambdaListVersionsByFunctionPaginator.EXPECT().NextPage(gomock.Any()).Return(&lambda.ListVersionsByFunctionOutput{
+		Versions: functionVersions,
+	}, nil)
+	mockLambdaListVersionsByFunctionPaginator.EXPECT().HasMorePages().Return(false)
+}
+
 func mockAppRunner(ctrl *gomock.Controller, mockAwsProvider *boxaws.MockProvider, mockResult []map[string]string) {
 	mockApprunnerListServicesPaginator := boxaws.NewMockAppRunnerListServicesPaginator(ctrl)
 	mockAwsProvider.MockAppRunnerPaginators.EXPECT().NewListServicesPaginator(gomock.Any()).Return(mockApprunnerListServicesPaginator)
@@ -0,0 +1,47 @@
+package kubernetes
+
+import (
+	gerrors "github.com/pkg/errors"
+	k8sclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Cluster is a Kubernetes cluster that is scanned for images in use.
+type Cluster struct {
+	Name   string
+	Client k8sclient.Interface
+}
+
+// NewClustersFromKubeconfigs creates a cluster for every kubeconfig file, using its current context.
+func NewClustersFromKubeconfigs(kubeconfigPaths []string) ([]Cluster, error) {
+	clusters := make([]Cluster, 0, len(kubeconfigPaths))
+
+	for _, kubeconfigPath := range kubeconfigPaths {
+		clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath},
+			&clientcmd.ConfigOverrides{},
+		)
+
+		rawConfig, err := clientConfig.RawConfig()
+		if err != nil {
+			return nil, gerrors.Wrapf(err, "cannot load kubeconfig %v", kubeconfigPath)
+		}
+
+		restConfig, err := clientConfig.ClientConfig()
+		if err != nil {
+			return nil, gerrors.Wrapf(err, "cannot create client config from kubeconfig %v", kubeconfigPath)
+		}
+
+		client, err := k8sclient.NewForConfig(restConfig)
+		if err != nil {
+			return nil, gerrors.Wrapf(err, "cannot create Kubernetes client from kubeconfig %v", kubeconfigPath)
+		}
+
+		clusters = append(clusters, Cluster{
+			Name:   rawConfig.CurrentContext,
+			Client: client,
+		})
+	}
+
+	return clusters, nil
+}
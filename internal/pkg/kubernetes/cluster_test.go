@@ -0,0 +1,72 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewClustersFromKubeconfigs(t *testing.T) {
+	t.Parallel()
+
+	apiServer := newFakeApiServer(t, "Bearer token1")
+
+	kubeconfigPath := filepath.Join(t.TempDir(), "kubeconfig")
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: context1
+clusters:
+- name: cluster1
+  cluster:
+    server: %v
+    certificate-authority-data: %v
+users:
+- name: user1
+  user:
+    token: token1
+contexts:
+- name: context1
+  context:
+    cluster: cluster1
+    user: user1
+`, apiServer.URL, base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: apiServer.Certificate().Raw,
+	})))
+
+	err := os.WriteFile(kubeconfigPath, []byte(kubeconfig), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clusters, err := NewClustersFromKubeconfigs([]string{kubeconfigPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(clusters) != 1 || clusters[0].Name != "context1" {
+		t.Fatalf("Unexpected clusters %v", clusters)
+	}
+
+	pods, err := clusters[0].Client.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods.Items) != 1 || pods.Items[0].Name != "pod1" {
+		t.Errorf("Unexpected pods %v", pods.Items)
+	}
+}
+
+func TestNewClustersFromMissingKubeconfig(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewClustersFromKubeconfigs([]string{filepath.Join(t.TempDir(), "missing")})
+	if err == nil {
+		t.Error("Expected error for a missing kubeconfig")
+	}
+}
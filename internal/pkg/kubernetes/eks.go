@@ -0,0 +1,128 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	boxaws "github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/aws"
+	gerrors "github.com/pkg/errors"
+	k8sclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	eksClusterIdHeader = "x-k8s-aws-id"
+	eksTokenPrefix     = "k8s-aws-v1."
+	// EKS tokens are valid for 15 minutes, they are refreshed earlier to never send an expired one
+	eksTokenRefreshPeriod = 10 * time.Minute
+)
+
+// NewEksClusters creates a cluster for every EKS cluster name, authenticating with the AWS credentials of the provider
+// the same way as "aws eks get-token" does.
+func NewEksClusters(ctx context.Context, awsProvider *boxaws.Provider, clusterNames []string) ([]Cluster, error) {
+	clusters := make([]Cluster, 0, len(clusterNames))
+
+	for _, clusterName := range clusterNames {
+		describeClusterOutput, err := awsProvider.EksClient.DescribeCluster(ctx, &eks.DescribeClusterInput{
+			Name: aws.String(clusterName),
+		})
+		if err != nil {
+			return nil, gerrors.Wrapf(err, "cannot describe EKS cluster %v", clusterName)
+		}
+
+		eksCluster := describeClusterOutput.Cluster
+		if eksCluster.Endpoint == nil || eksCluster.CertificateAuthority == nil || eksCluster.CertificateAuthority.Data == nil {
+			return nil, gerrors.Errorf("EKS cluster %v has no endpoint or certificate authority", clusterName)
+		}
+
+		caData, err := base64.StdEncoding.DecodeString(*eksCluster.CertificateAuthority.Data)
+		if err != nil {
+			return nil, gerrors.Wrapf(err, "cannot decode certificate authority of EKS cluster %v", clusterName)
+		}
+
+		tokenGenerator := &eksTokenGenerator{
+			stsPresignClient: awsProvider.StsPresignClient,
+			clusterName:      clusterName,
+		}
+
+		client, err := k8sclient.NewForConfig(&rest.Config{
+			Host: *eksCluster.Endpoint,
+			TLSClientConfig: rest.TLSClientConfig{
+				CAData: caData,
+			},
+			WrapTransport: func(roundTripper http.RoundTripper) http.RoundTripper {
+				return &eksTokenRoundTripper{
+					tokenGenerator: tokenGenerator,
+					next:           roundTripper,
+				}
+			},
+		})
+		if err != nil {
+			return nil, gerrors.Wrapf(err, "cannot create Kubernetes client for EKS cluster %v", clusterName)
+		}
+
+		clusters = append(clusters, Cluster{
+			Name:   clusterName,
+			Client: client,
+		})
+	}
+
+	return clusters, nil
+}
+
+type eksTokenGenerator struct {
+	stsPresignClient boxaws.StsPresignClient
+	clusterName      string
+
+	mutex     sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func (g *eksTokenGenerator) getToken(ctx context.Context) (string, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.token != "" && time.Now().Before(g.expiresAt) {
+		return g.token, nil
+	}
+
+	presignedRequest, err := g.stsPresignClient.PresignGetCallerIdentity(ctx, &sts.GetCallerIdentityInput{},
+		func(presignOptions *sts.PresignOptions) {
+			presignOptions.ClientOptions = append(presignOptions.ClientOptions, func(options *sts.Options) {
+				options.APIOptions = append(options.APIOptions, smithyhttp.AddHeaderValue(eksClusterIdHeader, g.clusterName))
+			})
+		})
+	if err != nil {
+		return "", gerrors.Wrapf(err, "cannot presign get caller identity request for EKS cluster %v", g.clusterName)
+	}
+
+	g.token = fmt.Sprintf("%v%v", eksTokenPrefix, base64.RawURLEncoding.EncodeToString([]byte(presignedRequest.URL)))
+	g.expiresAt = time.Now().Add(eksTokenRefreshPeriod)
+
+	return g.token, nil
+}
+
+type eksTokenRoundTripper struct {
+	tokenGenerator *eksTokenGenerator
+	next           http.RoundTripper
+}
+
+func (r *eksTokenRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	token, err := r.tokenGenerator.getToken(request.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	request = request.Clone(request.Context())
+	request.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
+
+	return r.next.RoundTrip(request)
+}
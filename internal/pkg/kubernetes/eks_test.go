@@ -0,0 +1,105 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	boxaws "github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/aws"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewEksClusters(t *testing.T) {
+	t.Parallel()
+
+	presignedUrl := "https://sts.mock-aws-region.amazonaws.com/?Action=GetCallerIdentity&Version=2011-06-15"
+	expectedAuthorization := fmt.Sprintf("Bearer k8s-aws-v1.%v", base64.RawURLEncoding.EncodeToString([]byte(presignedUrl)))
+
+	apiServer := newFakeApiServer(t, expectedAuthorization)
+
+	ctrl := gomock.NewController(t)
+	mockAwsProvider := boxaws.NewMockProvider(ctrl)
+
+	mockAwsProvider.MockEksClient.EXPECT().DescribeCluster(gomock.Any(), &eks.DescribeClusterInput{
+		Name: aws.String("cluster1"),
+	}).Return(&eks.DescribeClusterOutput{
+		Cluster: &ekstypes.Cluster{
+			Name:     aws.String("cluster1"),
+			Endpoint: aws.String(apiServer.URL),
+			CertificateAuthority: &ekstypes.Certificate{
+				Data: aws.String(base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{
+					Type:  "CERTIFICATE",
+					Bytes: apiServer.Certificate().Raw,
+				}))),
+			},
+		},
+	}, nil)
+
+	// the token is cached, so only one presign call is expected for multiple requests
+	mockAwsProvider.MockStsPresignClient.EXPECT().PresignGetCallerIdentity(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&v4.PresignedHTTPRequest{
+			URL:    presignedUrl,
+			Method: http.MethodGet,
+		}, nil)
+
+	clusters, err := NewEksClusters(context.TODO(), mockAwsProvider.Provider, []string{"cluster1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(clusters) != 1 || clusters[0].Name != "cluster1" {
+		t.Fatalf("Unexpected clusters %v", clusters)
+	}
+
+	for i := 0; i < 2; i++ {
+		pods, err := clusters[0].Client.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pods.Items) != 1 || pods.Items[0].Name != "pod1" {
+			t.Errorf("Unexpected pods %v", pods.Items)
+		}
+	}
+}
+
+// newFakeApiServer starts a fake Kubernetes API server that returns a single pod when called with the expected
+// authorization header.
+func newFakeApiServer(t *testing.T, expectedAuthorization string) *httptest.Server {
+	apiServer := httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if expectedAuthorization != "" && request.Header.Get("Authorization") != expectedAuthorization {
+			writer.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if request.URL.Path != "/api/v1/pods" {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		_, err := writer.Write([]byte(`{
+			"kind": "PodList",
+			"apiVersion": "v1",
+			"metadata": {},
+			"items": [
+				{
+					"metadata": {"name": "pod1", "namespace": "namespace1"},
+					"spec": {"containers": [{"name": "container1", "image": "image1:v1"}]}
+				}
+			]
+		}`))
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(apiServer.Close)
+
+	return apiServer
+}
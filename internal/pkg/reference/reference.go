@@ -0,0 +1,141 @@
+package reference
+
+import (
+	"fmt"
+	gerrors "github.com/pkg/errors"
+	"regexp"
+	"strings"
+)
+
+const DefaultTag = "latest"
+
+var (
+	pathComponentRegexp = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*$`)
+	tagRegexp           = regexp.MustCompile(`^\w[\w.-]{0,127}$`)
+	digestRegexp        = regexp.MustCompile(`^[a-z0-9]+(?:[+._-][a-z0-9]+)*:[a-f0-9]{32,}$`)
+
+	// ecrRegistryRegexp matches all private ECR endpoints: standard, China, FIPS, dual-stack and dual-stack FIPS
+	ecrRegistryRegexp = regexp.MustCompile(`^(\d{12})\.dkr[.-]ecr(?:-fips)?\.([a-z0-9-]+)\.(?:amazonaws\.com(?:\.cn)?|on\.aws)$`)
+)
+
+// Reference is a parsed image reference, e.g. 123456789012.dkr.ecr.eu-west-1.amazonaws.com/repository:tag@digest.
+type Reference struct {
+	// Registry is an empty string if the reference does not contain a registry host
+	Registry   string
+	Repository string
+	// Tag is an empty string if the reference contains only a digest
+	Tag    string
+	Digest string
+}
+
+// Parse parses and normalizes an image reference:
+//   - the registry host is lowercased and all ECR endpoint variants of the same registry are converted to the
+//     standard one (<account>.dkr.ecr.<region>.amazonaws.com),
+//   - the implicit latest tag is added if there is neither a tag nor a digest,
+//   - the digest is lowercased.
+//
+// An error is returned if the reference is invalid (e.g. contains an uppercase repository name).
+func Parse(image string) (Reference, error) {
+	var reference Reference
+
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		reference.Digest = strings.ToLower(name[i+1:])
+		name = name[:i]
+		if !digestRegexp.MatchString(reference.Digest) {
+			return Reference{}, gerrors.Errorf("invalid digest in image reference %v", image)
+		}
+	}
+
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		reference.Tag = name[i+1:]
+		name = name[:i]
+		if !tagRegexp.MatchString(reference.Tag) {
+			return Reference{}, gerrors.Errorf("invalid tag in image reference %v", image)
+		}
+	}
+
+	if i := strings.Index(name, "/"); i >= 0 && isRegistry(name[:i]) {
+		reference.Registry = normalizeRegistry(name[:i])
+		name = name[i+1:]
+	}
+
+	for _, pathComponent := range strings.Split(name, "/") {
+		if !pathComponentRegexp.MatchString(pathComponent) {
+			return Reference{}, gerrors.Errorf("invalid repository in image reference %v", image)
+		}
+	}
+	reference.Repository = name
+
+	if reference.Tag == "" && reference.Digest == "" {
+		reference.Tag = DefaultTag
+	}
+
+	return reference, nil
+}
+
+// Normalize returns normalized identifiers of an image reference: name:tag and name@digest (only the ones present in
+// the reference). Invalid references are returned as they are, so they can still be compared literally.
+func Normalize(image string) []string {
+	reference, err := Parse(image)
+	if err != nil {
+		return []string{image}
+	}
+
+	var ids []string
+	if tagId := reference.TagId(); tagId != "" {
+		ids = append(ids, tagId)
+	}
+	if digestId := reference.DigestId(); digestId != "" {
+		ids = append(ids, digestId)
+	}
+	return ids
+}
+
+// Name returns the registry and the repository of the reference.
+func (r Reference) Name() string {
+	if r.Registry == "" {
+		return r.Repository
+	}
+	return fmt.Sprintf("%v/%v", r.Registry, r.Repository)
+}
+
+// TagId returns the name:tag form of the reference or an empty string if the reference does not have a tag.
+func (r Reference) TagId() string {
+	if r.Tag == "" {
+		return ""
+	}
+	return fmt.Sprintf("%v:%v", r.Name(), r.Tag)
+}
+
+// DigestId returns the name@digest form of the reference or an empty string if the reference does not have a digest.
+func (r Reference) DigestId() string {
+	if r.Digest == "" {
+		return ""
+	}
+	return fmt.Sprintf("%v@%v", r.Name(), r.Digest)
+}
+
+func (r Reference) String() string {
+	result := r.Name()
+	if r.Tag != "" {
+		result = fmt.Sprintf("%v:%v", result, r.Tag)
+	}
+	if r.Digest != "" {
+		result = fmt.Sprintf("%v@%v", result, r.Digest)
+	}
+	return result
+}
+
+// isRegistry checks if the first path component is a registry host, the same way as Docker does.
+func isRegistry(component string) bool {
+	return strings.ContainsAny(component, ".:") || component == "localhost" || strings.ToLower(component) != component
+}
+
+func normalizeRegistry(registry string) string {
+	registry = strings.ToLower(registry)
+	if matches := ecrRegistryRegexp.FindStringSubmatch(registry); matches != nil {
+		return fmt.Sprintf("%v.dkr.ecr.%v.amazonaws.com", matches[1], matches[2])
+	}
+	return registry
+}
@@ -0,0 +1,316 @@
+package reference
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"testing"
+)
+
+const (
+	testDigest      = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	testDigestUpper = "sha256:0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF"
+	testRegistry    = "123456789012.dkr.ecr.eu-west-1.amazonaws.com"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		image         string
+		expected      Reference
+		expectedError bool
+	}{
+		"Repository only": {
+			image: "repo1",
+			expected: Reference{
+				Repository: "repo1",
+				Tag:        "latest",
+			},
+		},
+		"Repository with tag": {
+			image: "repo1:v1",
+			expected: Reference{
+				Repository: "repo1",
+				Tag:        "v1",
+			},
+		},
+		"Repository with path": {
+			image: "team1/repo1:v1",
+			expected: Reference{
+				Repository: "team1/repo1",
+				Tag:        "v1",
+			},
+		},
+		"Repository with separators": {
+			image: "team-1/repo__1.a_b:v1.0-rc_1",
+			expected: Reference{
+				Repository: "team-1/repo__1.a_b",
+				Tag:        "v1.0-rc_1",
+			},
+		},
+		"Repository with digest": {
+			image: "repo1@" + testDigest,
+			expected: Reference{
+				Repository: "repo1",
+				Digest:     testDigest,
+			},
+		},
+		"Repository with tag and digest": {
+			image: "repo1:v1@" + testDigest,
+			expected: Reference{
+				Repository: "repo1",
+				Tag:        "v1",
+				Digest:     testDigest,
+			},
+		},
+		"Uppercase digest": {
+			image: "repo1@" + testDigestUpper,
+			expected: Reference{
+				Repository: "repo1",
+				Digest:     testDigest,
+			},
+		},
+		"Tag case is preserved": {
+			image: "repo1:Release-V1",
+			expected: Reference{
+				Repository: "repo1",
+				Tag:        "Release-V1",
+			},
+		},
+		"ECR registry": {
+			image: testRegistry + "/team1/repo1:v1",
+			expected: Reference{
+				Registry:   testRegistry,
+				Repository: "team1/repo1",
+				Tag:        "v1",
+			},
+		},
+		"ECR registry without tag": {
+			image: testRegistry + "/repo1",
+			expected: Reference{
+				Registry:   testRegistry,
+				Repository: "repo1",
+				Tag:        "latest",
+			},
+		},
+		"Uppercase ECR registry": {
+			image: "123456789012.DKR.ECR.EU-WEST-1.AMAZONAWS.COM/repo1:v1",
+			expected: Reference{
+				Registry:   testRegistry,
+				Repository: "repo1",
+				Tag:        "v1",
+			},
+		},
+		"ECR China registry": {
+			image: "123456789012.dkr.ecr.cn-north-1.amazonaws.com.cn/repo1:v1",
+			expected: Reference{
+				Registry:   "123456789012.dkr.ecr.cn-north-1.amazonaws.com",
+				Repository: "repo1",
+				Tag:        "v1",
+			},
+		},
+		"ECR FIPS registry": {
+			image: "123456789012.dkr.ecr-fips.us-east-1.amazonaws.com/repo1:v1",
+			expected: Reference{
+				Registry:   "123456789012.dkr.ecr.us-east-1.amazonaws.com",
+				Repository: "repo1",
+				Tag:        "v1",
+			},
+		},
+		"ECR dual-stack registry": {
+			image: "123456789012.dkr-ecr.eu-west-1.on.aws/repo1:v1",
+			expected: Reference{
+				Registry:   testRegistry,
+				Repository: "repo1",
+				Tag:        "v1",
+			},
+		},
+		"ECR dual-stack FIPS registry": {
+			image: "123456789012.dkr-ecr-fips.us-east-1.on.aws/repo1:v1",
+			expected: Reference{
+				Registry:   "123456789012.dkr.ecr.us-east-1.amazonaws.com",
+				Repository: "repo1",
+				Tag:        "v1",
+			},
+		},
+		"ECR public registry is not changed": {
+			image: "public.ecr.aws/team1/repo1:v1",
+			expected: Reference{
+				Registry:   "public.ecr.aws",
+				Repository: "team1/repo1",
+				Tag:        "v1",
+			},
+		},
+		"Registry with port": {
+			image: "registry.example.com:5000/repo1",
+			expected: Reference{
+				Registry:   "registry.example.com:5000",
+				Repository: "repo1",
+				Tag:        "latest",
+			},
+		},
+		"Registry with port and tag": {
+			image: "registry.example.com:5000/repo1:v1",
+			expected: Reference{
+				Registry:   "registry.example.com:5000",
+				Repository: "repo1",
+				Tag:        "v1",
+			},
+		},
+		"Localhost registry": {
+			image: "localhost/repo1:v1",
+			expected: Reference{
+				Registry:   "localhost",
+				Repository: "repo1",
+				Tag:        "v1",
+			},
+		},
+		"Uppercase repository": {
+			image:         "Repo1:v1",
+			expectedError: true,
+		},
+		"Uppercase repository with registry": {
+			image:         testRegistry + "/Repo1:v1",
+			expectedError: true,
+		},
+		"Invalid tag": {
+			image:         "repo1:-v1",
+			expectedError: true,
+		},
+		"Invalid digest": {
+			image:         "repo1@sha256:abc",
+			expectedError: true,
+		},
+		"Empty tag": {
+			image:         "repo1:",
+			expectedError: true,
+		},
+		"Empty repository": {
+			image:         testRegistry + "/",
+			expectedError: true,
+		},
+		"Empty image": {
+			image:         "",
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := Parse(testCase.image)
+			if testCase.expectedError {
+				if err == nil {
+					t.Errorf("Expected error, got %v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result != testCase.expected {
+				t.Errorf("Result %v different than expected %v", result, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		image    string
+		expected []string
+	}{
+		"Implicit latest tag": {
+			image:    testRegistry + "/repo1",
+			expected: []string{testRegistry + "/repo1:latest"},
+		},
+		"Explicit latest tag": {
+			image:    testRegistry + "/repo1:latest",
+			expected: []string{testRegistry + "/repo1:latest"},
+		},
+		"Digest": {
+			image:    testRegistry + "/repo1@" + testDigestUpper,
+			expected: []string{testRegistry + "/repo1@" + testDigest},
+		},
+		"Tag and digest": {
+			image:    testRegistry + "/repo1:v1@" + testDigest,
+			expected: []string{testRegistry + "/repo1:v1", testRegistry + "/repo1@" + testDigest},
+		},
+		"Dual-stack endpoint": {
+			image:    "123456789012.dkr-ecr.eu-west-1.on.aws/repo1:v1",
+			expected: []string{testRegistry + "/repo1:v1"},
+		},
+		"Invalid reference is not changed": {
+			image:    "Repo1:v1",
+			expected: []string{"Repo1:v1"},
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := Normalize(testCase.image)
+
+			diff := cmp.Diff(testCase.expected, result)
+			if diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		reference Reference
+		expected  string
+	}{
+		"Repository with tag": {
+			reference: Reference{
+				Repository: "repo1",
+				Tag:        "v1",
+			},
+			expected: "repo1:v1",
+		},
+		"Registry, repository, tag and digest": {
+			reference: Reference{
+				Registry:   testRegistry,
+				Repository: "repo1",
+				Tag:        "v1",
+				Digest:     testDigest,
+			},
+			expected: testRegistry + "/repo1:v1@" + testDigest,
+		},
+		"Registry, repository and digest": {
+			reference: Reference{
+				Registry:   testRegistry,
+				Repository: "repo1",
+				Digest:     testDigest,
+			},
+			expected: testRegistry + "/repo1@" + testDigest,
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := testCase.reference.String()
+
+			if result != testCase.expected {
+				t.Errorf("Result %v different than expected %v", result, testCase.expected)
+			}
+		})
+	}
+}
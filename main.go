@@ -1,39 +1,188 @@
 package main
 
 import (
+	"context"
 	"github.com/aws/aws-lambda-go/lambda"
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/aws"
 	"github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/cleaner"
+	"github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/kubernetes"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 const DefaultKeepDays = 30
 
 func main() {
-	awsProvider, err := aws.NewProvider()
+	ctx := context.Background()
+
+	awsProvider, err := aws.NewProvider(ctx, getRetryConfig(os.LookupEnv))
+	if err != nil {
+		panic(err)
+	}
+
+	kubernetesClusters, err := getKubernetesClusters(ctx, awsProvider, os.LookupEnv)
 	if err != nil {
 		panic(err)
 	}
 
-	cleanerObj := cleaner.New(awsProvider, cleaner.Config{
-		DryRun:          getDryRun(os.LookupEnv),
-		DefaultKeepDays: getDefaultKeepDays(os.LookupEnv),
+	usageAwsProviders, err := getUsageAwsProviders(ctx, awsProvider, os.LookupEnv)
+	if err != nil {
+		panic(err)
+	}
+
+	policy, err := getPolicy(ctx, awsProvider, os.LookupEnv)
+	if err != nil {
+		panic(err)
+	}
+
+	defaultLifecyclePolicy, err := getDefaultLifecyclePolicy(os.LookupEnv)
+	if err != nil {
+		panic(err)
+	}
+
+	cleanerObj := cleaner.New(awsProvider, usageAwsProviders, kubernetesClusters, cleaner.Config{
+		DryRun:                  getDryRun(os.LookupEnv),
+		DefaultKeepDays:         getDefaultKeepDays(os.LookupEnv),
+		DefaultKeepCount:        getInt(os.LookupEnv, "DEFAULT_KEEP_COUNT", 0),
+		DefaultAgeBasis:         getAgeBasis(os.LookupEnv),
+		DefaultUntaggedKeepDays: getDefaultUntaggedKeepDays(os.LookupEnv),
+		Policy:                  policy,
+		RepositoryConcurrency:   getInt(os.LookupEnv, "REPOSITORY_CONCURRENCY", cleaner.DefaultRepositoryConcurrency),
+
+		EcrLifecyclePolicies:   getBool(os.LookupEnv, "ECR_LIFECYCLE_POLICIES", false),
+		DefaultLifecyclePolicy: defaultLifecyclePolicy,
+
+		ScheduledTasksEnabled:        getBool(os.LookupEnv, "SCHEDULED_TASKS_ENABLED", true),
+		LambdaReferencedVersionsOnly: getBool(os.LookupEnv, "LAMBDA_REFERENCED_VERSIONS_ONLY", false),
+		BatchEnabled:                 getBool(os.LookupEnv, "BATCH_ENABLED", true),
+		SageMakerEnabled:             getBool(os.LookupEnv, "SAGEMAKER_ENABLED", true),
+		CodeBuildEnabled:             getBool(os.LookupEnv, "CODEBUILD_ENABLED", true),
 	})
 
 	if isLambda(os.LookupEnv) {
-		lambda.Start(func() error {
-			return cleanerObj.Clean(time.Now())
+		lambda.Start(func(ctx context.Context) (cleaner.Result, error) {
+			return cleanerObj.Clean(ctx, time.Now())
 		})
 	} else {
-		err := cleanerObj.Clean(time.Now())
+		_, err := cleanerObj.Clean(ctx, time.Now())
 		if err != nil {
 			panic(err)
 		}
 	}
 }
 
+func getKubernetesClusters(ctx context.Context, awsProvider *aws.Provider, lookupEnv func(key string) (string, bool)) ([]kubernetes.Cluster, error) {
+	kubeconfigClusters, err := kubernetes.NewClustersFromKubeconfigs(getList(lookupEnv, "KUBECONFIGS"))
+	if err != nil {
+		return nil, err
+	}
+
+	eksClusters, err := kubernetes.NewEksClusters(ctx, awsProvider, getList(lookupEnv, "EKS_CLUSTERS"))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(kubeconfigClusters, eksClusters...), nil
+}
+
+// getUsageAwsProviders returns providers of additional regions and accounts (roles assumed in all regions) in which
+// used images are discovered.
+func getUsageAwsProviders(ctx context.Context, awsProvider *aws.Provider, lookupEnv func(key string) (string, bool)) ([]*aws.Provider, error) {
+	var usageRegions []string
+	usageRegionSet := make(map[string]struct{})
+	for _, region := range getList(lookupEnv, "USAGE_REGIONS") {
+		if _, ok := usageRegionSet[region]; ok || region == awsProvider.Region {
+			continue
+		}
+		usageRegionSet[region] = struct{}{}
+		usageRegions = append(usageRegions, region)
+	}
+
+	var usageAwsProviders []*aws.Provider
+	for _, region := range usageRegions {
+		usageAwsProviders = append(usageAwsProviders, awsProvider.ForRegion(region))
+	}
+
+	for _, role := range getUsageRoles(lookupEnv) {
+		roleAwsProvider, err := awsProvider.ForRole(ctx, role.roleArn, role.externalId)
+		if err != nil {
+			return nil, err
+		}
+
+		usageAwsProviders = append(usageAwsProviders, roleAwsProvider)
+		for _, region := range usageRegions {
+			usageAwsProviders = append(usageAwsProviders, roleAwsProvider.ForRegion(region))
+		}
+	}
+
+	return usageAwsProviders, nil
+}
+
+type usageRole struct {
+	roleArn    string
+	externalId string
+}
+
+// getUsageRoles returns roles listed in USAGE_ROLE_ARNS, every entry is either a role ARN or a roleArn=externalId pair
+// (split at the first =), roles without their own external ID use USAGE_ROLE_EXTERNAL_ID.
+func getUsageRoles(lookupEnv func(key string) (string, bool)) []usageRole {
+	defaultExternalId, _ := lookupEnv("USAGE_ROLE_EXTERNAL_ID")
+
+	var roles []usageRole
+	for _, entry := range getList(lookupEnv, "USAGE_ROLE_ARNS") {
+		roleArn, externalId, hasExternalId := strings.Cut(entry, "=")
+		if !hasExternalId {
+			externalId = defaultExternalId
+		}
+		roles = append(roles, usageRole{
+			roleArn:    strings.TrimSpace(roleArn),
+			externalId: strings.TrimSpace(externalId),
+		})
+	}
+	return roles
+}
+
+func getPolicy(ctx context.Context, awsProvider *aws.Provider, lookupEnv func(key string) (string, bool)) (cleaner.Policy, error) {
+	policyLocation, isPolicyLocationSet := lookupEnv("POLICY")
+	if !isPolicyLocationSet || policyLocation == "" {
+		return cleaner.Policy{}, nil
+	}
+	return cleaner.LoadPolicy(ctx, awsProvider, policyLocation)
+}
+
+func getDefaultLifecyclePolicy(lookupEnv func(key string) (string, bool)) (*cleaner.LifecyclePolicy, error) {
+	lifecyclePolicyFile, isLifecyclePolicyFileSet := lookupEnv("LIFECYCLE_POLICY_FILE")
+	if !isLifecyclePolicyFileSet || lifecyclePolicyFile == "" {
+		return nil, nil
+	}
+	return cleaner.LoadLifecyclePolicy(lifecyclePolicyFile)
+}
+
+// getRetryConfig returns the default retry settings overridden by the RETRY_* variables, invalid values are ignored.
+func getRetryConfig(lookupEnv func(key string) (string, bool)) aws.RetryConfig {
+	retryConfig := aws.DefaultRetryConfig()
+	retryModeStr, isRetryModeSet := lookupEnv("RETRY_MODE")
+	if isRetryModeSet {
+		parsedRetryMode, err := awssdk.ParseRetryMode(retryModeStr)
+		if err == nil {
+			retryConfig.Mode = parsedRetryMode
+		}
+	}
+	if maxAttempts := getInt(lookupEnv, "RETRY_MAX_ATTEMPTS", retryConfig.MaxAttempts); maxAttempts > 0 {
+		retryConfig.MaxAttempts = maxAttempts
+	}
+	if maxBackoffSeconds := getInt(lookupEnv, "RETRY_MAX_BACKOFF_SECONDS", int(retryConfig.MaxBackoff/time.Second)); maxBackoffSeconds > 0 {
+		retryConfig.MaxBackoff = time.Duration(maxBackoffSeconds) * time.Second
+	}
+	if retryTokens := getInt(lookupEnv, "RETRY_TOKENS", int(retryConfig.RetryTokens)); retryTokens > 0 {
+		retryConfig.RetryTokens = uint(retryTokens)
+	}
+	return retryConfig
+}
+
 func isLambda(lookupEnv func(key string) (string, bool)) bool {
 	_, result := lookupEnv("AWS_LAMBDA_FUNCTION_NAME")
 	return result
@@ -51,6 +200,27 @@ func getDefaultKeepDays(lookupEnv func(key string) (string, bool)) int {
 	return defaultKeepDays
 }
 
+// getDefaultUntaggedKeepDays returns nil if untagged images should be retained the same way as tagged ones.
+func getDefaultUntaggedKeepDays(lookupEnv func(key string) (string, bool)) *int {
+	defaultUntaggedKeepDaysStr, isDefaultUntaggedKeepDaysSet := lookupEnv("DEFAULT_UNTAGGED_KEEP_DAYS")
+	if isDefaultUntaggedKeepDaysSet {
+		parsedDefaultUntaggedKeepDays, err := strconv.Atoi(defaultUntaggedKeepDaysStr)
+		if err == nil && parsedDefaultUntaggedKeepDays >= 0 {
+			return &parsedDefaultUntaggedKeepDays
+		}
+	}
+	return nil
+}
+
+func getAgeBasis(lookupEnv func(key string) (string, bool)) cleaner.AgeBasis {
+	ageBasis := cleaner.AgeBasisPush
+	ageBasisStr, isAgeBasisSet := lookupEnv("AGE_BASIS")
+	if isAgeBasisSet && cleaner.AgeBasis(ageBasisStr).IsValid() {
+		ageBasis = cleaner.AgeBasis(ageBasisStr)
+	}
+	return ageBasis
+}
+
 func getDryRun(lookupEnv func(key string) (string, bool)) bool {
 	dryRun := true
 	dryRunStr, isDryRunSet := lookupEnv("DRY_RUN")
@@ -59,3 +229,41 @@ func getDryRun(lookupEnv func(key string) (string, bool)) bool {
 	}
 	return dryRun
 }
+
+func getBool(lookupEnv func(key string) (string, bool), key string, defaultValue bool) bool {
+	value := defaultValue
+	valueStr, isValueSet := lookupEnv(key)
+	if isValueSet {
+		parsedValue, err := strconv.ParseBool(valueStr)
+		if err == nil {
+			value = parsedValue
+		}
+	}
+	return value
+}
+
+func getInt(lookupEnv func(key string) (string, bool), key string, defaultValue int) int {
+	value := defaultValue
+	valueStr, isValueSet := lookupEnv(key)
+	if isValueSet {
+		parsedValue, err := strconv.Atoi(valueStr)
+		if err == nil {
+			value = parsedValue
+		}
+	}
+	return value
+}
+
+func getList(lookupEnv func(key string) (string, bool), key string) []string {
+	var values []string
+	valuesStr, isValuesSet := lookupEnv(key)
+	if isValuesSet {
+		for _, value := range strings.Split(valuesStr, ",") {
+			value = strings.TrimSpace(value)
+			if len(value) > 0 {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
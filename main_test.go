@@ -1,6 +1,17 @@
 package main
 
-import "testing"
+import (
+	"context"
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go/ptr"
+	"github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/aws"
+	"github.com/devopsbox-io/aws-ecr-cleaner/internal/pkg/cleaner"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
 
 func TestGetDefaultKeepDays(t *testing.T) {
 	t.Parallel()
@@ -42,6 +53,149 @@ func TestGetDefaultKeepDays(t *testing.T) {
 	}
 }
 
+func TestGetDefaultUntaggedKeepDays(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		env      map[string]string
+		expected *int
+	}{
+		"Env variable not set": {
+			env:      map[string]string{},
+			expected: nil,
+		},
+		"Env variable with invalid value": {
+			env: map[string]string{
+				"DEFAULT_UNTAGGED_KEEP_DAYS": "invalid",
+			},
+			expected: nil,
+		},
+		"Env variable with negative value": {
+			env: map[string]string{
+				"DEFAULT_UNTAGGED_KEEP_DAYS": "-1",
+			},
+			expected: nil,
+		},
+		"Env variable with zero": {
+			env: map[string]string{
+				"DEFAULT_UNTAGGED_KEEP_DAYS": "0",
+			},
+			expected: ptr.Int(0),
+		},
+		"Env variable with valid value": {
+			env: map[string]string{
+				"DEFAULT_UNTAGGED_KEEP_DAYS": "7",
+			},
+			expected: ptr.Int(7),
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := getDefaultUntaggedKeepDays(testLookupEnv(testCase.env))
+
+			if !reflect.DeepEqual(result, testCase.expected) {
+				t.Errorf("Result %v different than expected %v", result, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestGetAgeBasis(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		env      map[string]string
+		expected cleaner.AgeBasis
+	}{
+		"Env variable not set": {
+			env:      map[string]string{},
+			expected: cleaner.AgeBasisPush,
+		},
+		"Env variable with invalid value": {
+			env: map[string]string{
+				"AGE_BASIS": "invalid",
+			},
+			expected: cleaner.AgeBasisPush,
+		},
+		"Env variable with valid value": {
+			env: map[string]string{
+				"AGE_BASIS": "pull",
+			},
+			expected: cleaner.AgeBasisPull,
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := getAgeBasis(testLookupEnv(testCase.env))
+
+			if result != testCase.expected {
+				t.Errorf("Result %v different than expected %v", result, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestGetRetryConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		env      map[string]string
+		expected aws.RetryConfig
+	}{
+		"Env variables not set": {
+			env:      map[string]string{},
+			expected: aws.DefaultRetryConfig(),
+		},
+		"Env variables with invalid values": {
+			env: map[string]string{
+				"RETRY_MODE":                "fast",
+				"RETRY_MAX_ATTEMPTS":        "0",
+				"RETRY_MAX_BACKOFF_SECONDS": "invalid",
+				"RETRY_TOKENS":              "-1",
+			},
+			expected: aws.DefaultRetryConfig(),
+		},
+		"Env variables with valid values": {
+			env: map[string]string{
+				"RETRY_MODE":                "standard",
+				"RETRY_MAX_ATTEMPTS":        "3",
+				"RETRY_MAX_BACKOFF_SECONDS": "5",
+				"RETRY_TOKENS":              "1000",
+			},
+			expected: aws.RetryConfig{
+				Mode:        awssdk.RetryModeStandard,
+				MaxAttempts: 3,
+				MaxBackoff:  5 * time.Second,
+				RetryTokens: 1000,
+			},
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := getRetryConfig(testLookupEnv(testCase.env))
+
+			if result != testCase.expected {
+				t.Errorf("Result %v different than expected %v", result, testCase.expected)
+			}
+		})
+	}
+}
+
 func TestGetDryRun(t *testing.T) {
 	t.Parallel()
 
@@ -122,9 +276,317 @@ func TestIsLambda(t *testing.T) {
 	}
 }
 
+func TestGetBool(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		env          map[string]string
+		defaultValue bool
+		expected     bool
+	}{
+		"Env variable not set, default true": {
+			env:          map[string]string{},
+			defaultValue: true,
+			expected:     true,
+		},
+		"Env variable not set, default false": {
+			env:          map[string]string{},
+			defaultValue: false,
+			expected:     false,
+		},
+		"Env variable with invalid value": {
+			env: map[string]string{
+				"SOME_BOOL": "invalid",
+			},
+			defaultValue: true,
+			expected:     true,
+		},
+		"Env variable with valid false value": {
+			env: map[string]string{
+				"SOME_BOOL": "false",
+			},
+			defaultValue: true,
+			expected:     false,
+		},
+		"Env variable with valid true value": {
+			env: map[string]string{
+				"SOME_BOOL": "true",
+			},
+			defaultValue: false,
+			expected:     true,
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := getBool(testLookupEnv(testCase.env), "SOME_BOOL", testCase.defaultValue)
+
+			if result != testCase.expected {
+				t.Errorf("Result %v different than expected %v", result, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestGetInt(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		env          map[string]string
+		defaultValue int
+		expected     int
+	}{
+		"Env variable not set": {
+			env:          map[string]string{},
+			defaultValue: 5,
+			expected:     5,
+		},
+		"Env variable with invalid value": {
+			env: map[string]string{
+				"SOME_INT": "invalid",
+			},
+			defaultValue: 5,
+			expected:     5,
+		},
+		"Env variable with valid value": {
+			env: map[string]string{
+				"SOME_INT": "10",
+			},
+			defaultValue: 5,
+			expected:     10,
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := getInt(testLookupEnv(testCase.env), "SOME_INT", testCase.defaultValue)
+
+			if result != testCase.expected {
+				t.Errorf("Result %v different than expected %v", result, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestGetList(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		env      map[string]string
+		expected []string
+	}{
+		"Env variable not set": {
+			env:      map[string]string{},
+			expected: nil,
+		},
+		"Env variable empty": {
+			env: map[string]string{
+				"SOME_LIST": "",
+			},
+			expected: nil,
+		},
+		"Env variable with single value": {
+			env: map[string]string{
+				"SOME_LIST": "value1",
+			},
+			expected: []string{"value1"},
+		},
+		"Env variable with multiple values": {
+			env: map[string]string{
+				"SOME_LIST": "value1, value2,,value3 ",
+			},
+			expected: []string{"value1", "value2", "value3"},
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := getList(testLookupEnv(testCase.env), "SOME_LIST")
+
+			if !reflect.DeepEqual(result, testCase.expected) {
+				t.Errorf("Result %v different than expected %v", result, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestGetUsageAwsProviders(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		env      map[string]string
+		expected []string
+	}{
+		"Env variable not set": {
+			env:      map[string]string{},
+			expected: nil,
+		},
+		"Env variable with other regions": {
+			env: map[string]string{
+				"USAGE_REGIONS": "us-east-1,eu-central-1",
+			},
+			expected: []string{"us-east-1", "eu-central-1"},
+		},
+		"Env variable with own and duplicated regions": {
+			env: map[string]string{
+				"USAGE_REGIONS": "eu-west-1,us-east-1,us-east-1",
+			},
+			expected: []string{"us-east-1"},
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			awsProvider := aws.NewProviderForConfig(awssdk.Config{
+				Region: "eu-west-1",
+			})
+
+			usageAwsProviders, err := getUsageAwsProviders(context.TODO(), awsProvider, testLookupEnv(testCase.env))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var result []string
+			for _, usageAwsProvider := range usageAwsProviders {
+				result = append(result, usageAwsProvider.Region)
+			}
+
+			if !reflect.DeepEqual(result, testCase.expected) {
+				t.Errorf("Result %v different than expected %v", result, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestGetUsageRoles(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		env      map[string]string
+		expected []usageRole
+	}{
+		"Env variable not set": {
+			env:      map[string]string{},
+			expected: nil,
+		},
+		"Roles without external ids": {
+			env: map[string]string{
+				"USAGE_ROLE_ARNS": "arn:aws:iam::111111111111:role/role1,arn:aws:iam::222222222222:role/role2",
+			},
+			expected: []usageRole{
+				{roleArn: "arn:aws:iam::111111111111:role/role1"},
+				{roleArn: "arn:aws:iam::222222222222:role/role2"},
+			},
+		},
+		"Roles with the default external id": {
+			env: map[string]string{
+				"USAGE_ROLE_ARNS":        "arn:aws:iam::111111111111:role/role1,arn:aws:iam::222222222222:role/role2",
+				"USAGE_ROLE_EXTERNAL_ID": "defaultId",
+			},
+			expected: []usageRole{
+				{roleArn: "arn:aws:iam::111111111111:role/role1", externalId: "defaultId"},
+				{roleArn: "arn:aws:iam::222222222222:role/role2", externalId: "defaultId"},
+			},
+		},
+		"Roles with their own external ids": {
+			env: map[string]string{
+				"USAGE_ROLE_ARNS":        "arn:aws:iam::111111111111:role/role1=id1, arn:aws:iam::222222222222:role/role2 = id2=,arn:aws:iam::333333333333:role/role3",
+				"USAGE_ROLE_EXTERNAL_ID": "defaultId",
+			},
+			expected: []usageRole{
+				{roleArn: "arn:aws:iam::111111111111:role/role1", externalId: "id1"},
+				{roleArn: "arn:aws:iam::222222222222:role/role2", externalId: "id2="},
+				{roleArn: "arn:aws:iam::333333333333:role/role3", externalId: "defaultId"},
+			},
+		},
+		"Role with an empty external id": {
+			env: map[string]string{
+				"USAGE_ROLE_ARNS":        "arn:aws:iam::111111111111:role/role1=",
+				"USAGE_ROLE_EXTERNAL_ID": "defaultId",
+			},
+			expected: []usageRole{
+				{roleArn: "arn:aws:iam::111111111111:role/role1"},
+			},
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := getUsageRoles(testLookupEnv(testCase.env))
+
+			if !reflect.DeepEqual(result, testCase.expected) {
+				t.Errorf("Result %v different than expected %v", result, testCase.expected)
+			}
+		})
+	}
+}
+
 func testLookupEnv(env map[string]string) func(key string) (string, bool) {
 	return func(key string) (string, bool) {
 		result, exists := env[key]
 		return result, exists
 	}
 }
+
+func TestGetPolicy(t *testing.T) {
+	t.Parallel()
+
+	policyFile := filepath.Join(t.TempDir(), "policy.yaml")
+	err := os.WriteFile(policyFile, []byte("repositories:\n  - selector:\n      namePrefix: team-a/\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		env                  map[string]string
+		expectedRepositories int
+	}{
+		"Env variable not set": {
+			env:                  map[string]string{},
+			expectedRepositories: 0,
+		},
+		"Policy file": {
+			env: map[string]string{
+				"POLICY": policyFile,
+			},
+			expectedRepositories: 1,
+		},
+	}
+
+	for name, testCase := range tests {
+		// capture range variables
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := getPolicy(context.TODO(), aws.NewProviderForConfig(awssdk.Config{Region: "eu-west-1"}), testLookupEnv(testCase.env))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(result.Repositories) != testCase.expectedRepositories {
+				t.Errorf("Result %v different than expected %v repositories", result, testCase.expectedRepositories)
+			}
+		})
+	}
+}